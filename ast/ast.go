@@ -45,6 +45,7 @@ func (p *Program) String() string {
 type LetStatement struct {
 	Token      token.Token
 	Identifier *Identifier
+	Annotation *TypeAnnotation // nil when the type is left to inference
 	Value      Expression
 }
 
@@ -58,7 +59,11 @@ func (ls *LetStatement) statementNode()  {}
 func (ls *LetStatement) String() string {
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf("let %s = ", ls.Identifier.String()))
+	sb.WriteString(fmt.Sprintf("let %s", ls.Identifier.String()))
+	if ls.Annotation != nil {
+		sb.WriteString(": " + ls.Annotation.String())
+	}
+	sb.WriteString(" = ")
 
 	if ls.Value != nil {
 		sb.WriteString(ls.Value.String())
@@ -68,6 +73,38 @@ func (ls *LetStatement) String() string {
 	return sb.String()
 }
 
+// ConstStatement binds Identifier to Value like a LetStatement, but the
+// binding can never be assigned to.
+type ConstStatement struct {
+	Token      token.Token
+	Identifier *Identifier
+	Annotation *TypeAnnotation // nil when the type is left to inference
+	Value      Expression
+}
+
+func (cs *ConstStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+func (cs *ConstStatement) statementNode() {}
+
+func (cs *ConstStatement) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("const %s", cs.Identifier.String()))
+	if cs.Annotation != nil {
+		sb.WriteString(": " + cs.Annotation.String())
+	}
+	sb.WriteString(" = ")
+
+	if cs.Value != nil {
+		sb.WriteString(cs.Value.String())
+	}
+	sb.WriteString(";")
+
+	return sb.String()
+}
+
 type ReturnStatement struct {
 	Token       token.Token
 	ReturnValue Expression
@@ -86,6 +123,7 @@ func (rs *ReturnStatement) String() string {
 	sb.WriteString("return")
 
 	if rs.ReturnValue != nil {
+		sb.WriteString(" ")
 		sb.WriteString(rs.ReturnValue.String())
 	}
 	sb.WriteString(";")
@@ -146,6 +184,21 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+type BooleanLiteral struct {
+	Token token.Token
+	Value bool
+}
+
+func (bl *BooleanLiteral) TokenLiteral() string {
+	return bl.Token.Literal
+}
+
+func (bl *BooleanLiteral) expressionNode() {}
+
+func (bl *BooleanLiteral) String() string {
+	return bl.Token.Literal
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -163,7 +216,252 @@ func (pe *PrefixExpression) String() string {
 
 	sb.WriteString(pe.Operator)
 	sb.WriteString(pe.Right.String())
-	sb.WriteString(";")
+
+	return sb.String()
+}
+
+type InfixExpression struct {
+	Token    token.Token
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+func (ie *InfixExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+
+func (ie *InfixExpression) expressionNode() {}
+
+func (ie *InfixExpression) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("(")
+	sb.WriteString(ie.Left.String())
+	sb.WriteString(" " + ie.Operator + " ")
+	sb.WriteString(ie.Right.String())
+	sb.WriteString(")")
+
+	return sb.String()
+}
+
+type BlockStatement struct {
+	Token      token.Token
+	Statements []Statement
+}
+
+func (bs *BlockStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs *BlockStatement) statementNode() {}
+
+func (bs *BlockStatement) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("{")
+	for _, stmt := range bs.Statements {
+		sb.WriteString(stmt.String())
+	}
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+type WhileStatement struct {
+	Token     token.Token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (ws *WhileStatement) TokenLiteral() string {
+	return ws.Token.Literal
+}
+
+func (ws *WhileStatement) statementNode() {}
+
+func (ws *WhileStatement) String() string {
+	return fmt.Sprintf("while (%s) %s", ws.Condition.String(), ws.Body.String())
+}
+
+// ForInStatement binds Variable to each element of Iterable in turn and runs
+// Body for it.
+type ForInStatement struct {
+	Token    token.Token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForInStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+
+func (fs *ForInStatement) statementNode() {}
+
+func (fs *ForInStatement) String() string {
+	return fmt.Sprintf("for (%s in %s) %s", fs.Variable.String(), fs.Iterable.String(), fs.Body.String())
+}
+
+type BreakStatement struct {
+	Token token.Token
+}
+
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+
+func (bs *BreakStatement) statementNode() {}
+
+func (bs *BreakStatement) String() string {
+	return "break;"
+}
+
+type ContinueStatement struct {
+	Token token.Token
+}
+
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+
+func (cs *ContinueStatement) statementNode() {}
+
+func (cs *ContinueStatement) String() string {
+	return "continue;"
+}
+
+// AssignExpression updates an existing binding. Operator is "=" or a
+// compound operator such as "+=". The binding updated is the one in the
+// innermost enclosing scope that declares Target; assigning to a name that
+// isn't declared in any enclosing scope is an error.
+type AssignExpression struct {
+	Token    token.Token
+	Target   *Identifier
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+
+func (ae *AssignExpression) expressionNode() {}
+
+func (ae *AssignExpression) String() string {
+	return fmt.Sprintf("%s %s %s", ae.Target.String(), ae.Operator, ae.Value.String())
+}
+
+// TypeAnnotation is an optional type written after a declared name, as in
+// let x: int = 5;.
+type TypeAnnotation struct {
+	Token token.Token
+	Name  string
+}
+
+func (ta *TypeAnnotation) TokenLiteral() string {
+	return ta.Token.Literal
+}
+
+func (ta *TypeAnnotation) String() string {
+	return ta.Name
+}
+
+type StringLiteral struct {
+	Token token.Token
+	Value string
+}
+
+func (sl *StringLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+
+func (sl *StringLiteral) expressionNode() {}
+
+func (sl *StringLiteral) String() string {
+	return `"` + sl.Value + `"`
+}
+
+// ImportStatement loads the module at Path and binds it to Name, which the
+// parser derives from the last element of Path without its .mk extension.
+type ImportStatement struct {
+	Token token.Token
+	Path  *StringLiteral
+	Name  *Identifier
+}
+
+func (is *ImportStatement) TokenLiteral() string {
+	return is.Token.Literal
+}
+
+func (is *ImportStatement) statementNode() {}
+
+func (is *ImportStatement) String() string {
+	return fmt.Sprintf("import %s;", is.Path.String())
+}
+
+// ExportStatement makes the binding declared by Statement, a let or const
+// statement at the top level of a module, visible to modules importing it.
+type ExportStatement struct {
+	Token     token.Token
+	Statement Statement
+}
+
+func (es *ExportStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+func (es *ExportStatement) statementNode() {}
+
+func (es *ExportStatement) String() string {
+	return "export " + es.Statement.String()
+}
+
+type ThrowStatement struct {
+	Token token.Token
+	Value Expression
+}
+
+func (ts *ThrowStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+func (ts *ThrowStatement) statementNode() {}
+
+func (ts *ThrowStatement) String() string {
+	return fmt.Sprintf("throw %s;", ts.Value.String())
+}
+
+// TryStatement runs Body. If it throws, CatchParam is bound to the thrown
+// value and Catch is run. Finally, if present, runs last in either case. At
+// least one of Catch and Finally is present; CatchParam is nil exactly when
+// Catch is.
+type TryStatement struct {
+	Token      token.Token
+	Body       *BlockStatement
+	CatchParam *Identifier
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (ts *TryStatement) TokenLiteral() string {
+	return ts.Token.Literal
+}
+
+func (ts *TryStatement) statementNode() {}
+
+func (ts *TryStatement) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("try ")
+	sb.WriteString(ts.Body.String())
+	if ts.Catch != nil {
+		sb.WriteString(fmt.Sprintf(" catch (%s) %s", ts.CatchParam.String(), ts.Catch.String()))
+	}
+	if ts.Finally != nil {
+		sb.WriteString(" finally ")
+		sb.WriteString(ts.Finally.String())
+	}
 
 	return sb.String()
 }
@@ -0,0 +1,816 @@
+package ast
+
+import (
+	"../token"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// JSON schema
+//
+// MarshalJSON encodes every node as a JSON object whose "type" member names
+// the node type. Nodes that carry a token encode it under "token" as
+//
+//	{"type": "LET", "literal": "let", "line": 1, "column": 1}
+//
+// where line and column are 0 when the position is unknown. The remaining
+// members depend on the node type; an absent optional child is null.
+//
+//	Program             "statements": [Statement, ...]
+//	LetStatement        "token", "identifier": Identifier,
+//	                    "annotation": TypeAnnotation | null, "value": Expression
+//	ConstStatement      same members as LetStatement
+//	ReturnStatement     "token", "returnValue": Expression | null
+//	ExpressionStatement "token", "expression": Expression
+//	Identifier          "token", "value": string
+//	IntegerLiteral      "token", "value": number
+//	BooleanLiteral      "token", "value": boolean
+//	PrefixExpression    "token", "operator": string, "right": Expression
+//	InfixExpression     "token", "left": Expression, "operator": string,
+//	                    "right": Expression
+//	BlockStatement      "token", "statements": [Statement, ...]
+//	WhileStatement      "token", "condition": Expression, "body": BlockStatement
+//	ForInStatement      "token", "variable": Identifier, "iterable": Expression,
+//	                    "body": BlockStatement
+//	BreakStatement      "token"
+//	ContinueStatement   "token"
+//	AssignExpression    "token", "target": Identifier, "operator": string,
+//	                    "value": Expression
+//	TypeAnnotation      "token", "name": string
+//	StringLiteral       "token", "value": string
+//	ImportStatement     "token", "path": StringLiteral, "name": Identifier
+//	ExportStatement     "token", "statement": Statement
+//	ThrowStatement      "token", "value": Expression
+//	TryStatement        "token", "body": BlockStatement,
+//	                    "catchParam": Identifier | null,
+//	                    "catch": BlockStatement | null,
+//	                    "finally": BlockStatement | null
+//
+// UnmarshalJSON accepts exactly this schema, so decoding the output of
+// MarshalJSON yields a tree equal to the one that was encoded. Input that
+// doesn't match the schema is rejected: a required member that is absent or
+// null, a child of the wrong node type, an export of anything but a let or
+// const statement, or a try statement whose catch parameter and catch clause
+// aren't both present or both absent, or that has neither catch nor finally.
+
+type jsonToken struct {
+	Type    token.TokenType `json:"type"`
+	Literal string          `json:"literal"`
+	Line    int             `json:"line"`
+	Column  int             `json:"column"`
+}
+
+type jsonProgram struct {
+	Type       string            `json:"type"`
+	Statements []json.RawMessage `json:"statements"`
+}
+
+// jsonLetStatement also encodes ConstStatement, which has the same shape.
+type jsonLetStatement struct {
+	Type       string          `json:"type"`
+	Token      jsonToken       `json:"token"`
+	Identifier json.RawMessage `json:"identifier"`
+	Annotation json.RawMessage `json:"annotation"`
+	Value      json.RawMessage `json:"value"`
+}
+
+type jsonTypeAnnotation struct {
+	Type  string    `json:"type"`
+	Token jsonToken `json:"token"`
+	Name  string    `json:"name"`
+}
+
+type jsonReturnStatement struct {
+	Type        string          `json:"type"`
+	Token       jsonToken       `json:"token"`
+	ReturnValue json.RawMessage `json:"returnValue"`
+}
+
+type jsonExpressionStatement struct {
+	Type       string          `json:"type"`
+	Token      jsonToken       `json:"token"`
+	Expression json.RawMessage `json:"expression"`
+}
+
+type jsonIdentifier struct {
+	Type  string    `json:"type"`
+	Token jsonToken `json:"token"`
+	Value string    `json:"value"`
+}
+
+type jsonIntegerLiteral struct {
+	Type  string    `json:"type"`
+	Token jsonToken `json:"token"`
+	Value int       `json:"value"`
+}
+
+type jsonBooleanLiteral struct {
+	Type  string    `json:"type"`
+	Token jsonToken `json:"token"`
+	Value bool      `json:"value"`
+}
+
+type jsonPrefixExpression struct {
+	Type     string          `json:"type"`
+	Token    jsonToken       `json:"token"`
+	Operator string          `json:"operator"`
+	Right    json.RawMessage `json:"right"`
+}
+
+type jsonInfixExpression struct {
+	Type     string          `json:"type"`
+	Token    jsonToken       `json:"token"`
+	Left     json.RawMessage `json:"left"`
+	Operator string          `json:"operator"`
+	Right    json.RawMessage `json:"right"`
+}
+
+type jsonBlockStatement struct {
+	Type       string            `json:"type"`
+	Token      jsonToken         `json:"token"`
+	Statements []json.RawMessage `json:"statements"`
+}
+
+type jsonWhileStatement struct {
+	Type      string          `json:"type"`
+	Token     jsonToken       `json:"token"`
+	Condition json.RawMessage `json:"condition"`
+	Body      json.RawMessage `json:"body"`
+}
+
+type jsonForInStatement struct {
+	Type     string          `json:"type"`
+	Token    jsonToken       `json:"token"`
+	Variable json.RawMessage `json:"variable"`
+	Iterable json.RawMessage `json:"iterable"`
+	Body     json.RawMessage `json:"body"`
+}
+
+type jsonAssignExpression struct {
+	Type     string          `json:"type"`
+	Token    jsonToken       `json:"token"`
+	Target   json.RawMessage `json:"target"`
+	Operator string          `json:"operator"`
+	Value    json.RawMessage `json:"value"`
+}
+
+type jsonStringLiteral struct {
+	Type  string    `json:"type"`
+	Token jsonToken `json:"token"`
+	Value string    `json:"value"`
+}
+
+type jsonImportStatement struct {
+	Type  string          `json:"type"`
+	Token jsonToken       `json:"token"`
+	Path  json.RawMessage `json:"path"`
+	Name  json.RawMessage `json:"name"`
+}
+
+type jsonExportStatement struct {
+	Type      string          `json:"type"`
+	Token     jsonToken       `json:"token"`
+	Statement json.RawMessage `json:"statement"`
+}
+
+type jsonThrowStatement struct {
+	Type  string          `json:"type"`
+	Token jsonToken       `json:"token"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonTryStatement struct {
+	Type       string          `json:"type"`
+	Token      jsonToken       `json:"token"`
+	Body       json.RawMessage `json:"body"`
+	CatchParam json.RawMessage `json:"catchParam"`
+	Catch      json.RawMessage `json:"catch"`
+	Finally    json.RawMessage `json:"finally"`
+}
+
+// jsonTokenOnly encodes nodes that consist of nothing but their token.
+type jsonTokenOnly struct {
+	Type  string    `json:"type"`
+	Token jsonToken `json:"token"`
+}
+
+// MarshalJSON encodes the tree rooted at node following the schema above.
+func MarshalJSON(node Node) ([]byte, error) {
+	switch n := node.(type) {
+	case *Program:
+		statements, err := marshalStatements(n.Statements)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonProgram{"Program", statements})
+
+	case *LetStatement:
+		identifier, err := marshalChild(n.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		annotation, err := marshalChild(n.Annotation)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalChild(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonLetStatement{"LetStatement", toJSONToken(n.Token), identifier, annotation, value})
+
+	case *ConstStatement:
+		identifier, err := marshalChild(n.Identifier)
+		if err != nil {
+			return nil, err
+		}
+		annotation, err := marshalChild(n.Annotation)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalChild(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonLetStatement{"ConstStatement", toJSONToken(n.Token), identifier, annotation, value})
+
+	case *ReturnStatement:
+		returnValue, err := marshalChild(n.ReturnValue)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonReturnStatement{"ReturnStatement", toJSONToken(n.Token), returnValue})
+
+	case *ExpressionStatement:
+		expression, err := marshalChild(n.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonExpressionStatement{"ExpressionStatement", toJSONToken(n.Token), expression})
+
+	case *TypeAnnotation:
+		return json.Marshal(jsonTypeAnnotation{"TypeAnnotation", toJSONToken(n.Token), n.Name})
+
+	case *Identifier:
+		return json.Marshal(jsonIdentifier{"Identifier", toJSONToken(n.Token), n.Value})
+
+	case *IntegerLiteral:
+		return json.Marshal(jsonIntegerLiteral{"IntegerLiteral", toJSONToken(n.Token), n.Value})
+
+	case *BooleanLiteral:
+		return json.Marshal(jsonBooleanLiteral{"BooleanLiteral", toJSONToken(n.Token), n.Value})
+
+	case *PrefixExpression:
+		right, err := marshalChild(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonPrefixExpression{"PrefixExpression", toJSONToken(n.Token), n.Operator, right})
+
+	case *InfixExpression:
+		left, err := marshalChild(n.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := marshalChild(n.Right)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonInfixExpression{"InfixExpression", toJSONToken(n.Token), left, n.Operator, right})
+
+	case *BlockStatement:
+		statements, err := marshalStatements(n.Statements)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonBlockStatement{"BlockStatement", toJSONToken(n.Token), statements})
+
+	case *WhileStatement:
+		condition, err := marshalChild(n.Condition)
+		if err != nil {
+			return nil, err
+		}
+		body, err := marshalChild(n.Body)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonWhileStatement{"WhileStatement", toJSONToken(n.Token), condition, body})
+
+	case *ForInStatement:
+		variable, err := marshalChild(n.Variable)
+		if err != nil {
+			return nil, err
+		}
+		iterable, err := marshalChild(n.Iterable)
+		if err != nil {
+			return nil, err
+		}
+		body, err := marshalChild(n.Body)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonForInStatement{"ForInStatement", toJSONToken(n.Token), variable, iterable, body})
+
+	case *BreakStatement:
+		return json.Marshal(jsonTokenOnly{"BreakStatement", toJSONToken(n.Token)})
+
+	case *ContinueStatement:
+		return json.Marshal(jsonTokenOnly{"ContinueStatement", toJSONToken(n.Token)})
+
+	case *AssignExpression:
+		target, err := marshalChild(n.Target)
+		if err != nil {
+			return nil, err
+		}
+		value, err := marshalChild(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonAssignExpression{"AssignExpression", toJSONToken(n.Token), target, n.Operator, value})
+
+	case *StringLiteral:
+		return json.Marshal(jsonStringLiteral{"StringLiteral", toJSONToken(n.Token), n.Value})
+
+	case *ImportStatement:
+		path, err := marshalChild(n.Path)
+		if err != nil {
+			return nil, err
+		}
+		name, err := marshalChild(n.Name)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonImportStatement{"ImportStatement", toJSONToken(n.Token), path, name})
+
+	case *ExportStatement:
+		statement, err := marshalChild(n.Statement)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonExportStatement{"ExportStatement", toJSONToken(n.Token), statement})
+
+	case *ThrowStatement:
+		value, err := marshalChild(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonThrowStatement{"ThrowStatement", toJSONToken(n.Token), value})
+
+	case *TryStatement:
+		body, err := marshalChild(n.Body)
+		if err != nil {
+			return nil, err
+		}
+		catchParam, err := marshalChild(n.CatchParam)
+		if err != nil {
+			return nil, err
+		}
+		catch, err := marshalChild(n.Catch)
+		if err != nil {
+			return nil, err
+		}
+		finally, err := marshalChild(n.Finally)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jsonTryStatement{"TryStatement", toJSONToken(n.Token), body, catchParam, catch, finally})
+
+	default:
+		return nil, fmt.Errorf("ast.MarshalJSON: unexpected node type %T", n)
+	}
+}
+
+// UnmarshalJSON decodes a tree encoded by MarshalJSON.
+func UnmarshalJSON(data []byte) (Node, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	switch header.Type {
+	case "Program":
+		var jp jsonProgram
+		if err := json.Unmarshal(data, &jp); err != nil {
+			return nil, err
+		}
+		statements, err := unmarshalStatements(jp.Statements)
+		if err != nil {
+			return nil, err
+		}
+		return &Program{Statements: statements}, nil
+
+	case "LetStatement":
+		var jls jsonLetStatement
+		if err := json.Unmarshal(data, &jls); err != nil {
+			return nil, err
+		}
+		ident, err := unmarshalIdentifier(jls.Identifier, "LetStatement", "identifier", required)
+		if err != nil {
+			return nil, err
+		}
+		annotation, err := unmarshalAnnotation(jls.Annotation, "LetStatement", "annotation", optional)
+		if err != nil {
+			return nil, err
+		}
+		value, err := unmarshalExpression(jls.Value, "LetStatement", "value", required)
+		if err != nil {
+			return nil, err
+		}
+		return &LetStatement{Token: fromJSONToken(jls.Token), Identifier: ident, Annotation: annotation, Value: value}, nil
+
+	case "ConstStatement":
+		var jcs jsonLetStatement
+		if err := json.Unmarshal(data, &jcs); err != nil {
+			return nil, err
+		}
+		ident, err := unmarshalIdentifier(jcs.Identifier, "ConstStatement", "identifier", required)
+		if err != nil {
+			return nil, err
+		}
+		annotation, err := unmarshalAnnotation(jcs.Annotation, "ConstStatement", "annotation", optional)
+		if err != nil {
+			return nil, err
+		}
+		value, err := unmarshalExpression(jcs.Value, "ConstStatement", "value", required)
+		if err != nil {
+			return nil, err
+		}
+		return &ConstStatement{Token: fromJSONToken(jcs.Token), Identifier: ident, Annotation: annotation, Value: value}, nil
+
+	case "ReturnStatement":
+		var jrs jsonReturnStatement
+		if err := json.Unmarshal(data, &jrs); err != nil {
+			return nil, err
+		}
+		returnValue, err := unmarshalExpression(jrs.ReturnValue, "ReturnStatement", "returnValue", optional)
+		if err != nil {
+			return nil, err
+		}
+		return &ReturnStatement{Token: fromJSONToken(jrs.Token), ReturnValue: returnValue}, nil
+
+	case "ExpressionStatement":
+		var jes jsonExpressionStatement
+		if err := json.Unmarshal(data, &jes); err != nil {
+			return nil, err
+		}
+		expression, err := unmarshalExpression(jes.Expression, "ExpressionStatement", "expression", required)
+		if err != nil {
+			return nil, err
+		}
+		return &ExpressionStatement{Token: fromJSONToken(jes.Token), Expression: expression}, nil
+
+	case "TypeAnnotation":
+		var jta jsonTypeAnnotation
+		if err := json.Unmarshal(data, &jta); err != nil {
+			return nil, err
+		}
+		return &TypeAnnotation{Token: fromJSONToken(jta.Token), Name: jta.Name}, nil
+
+	case "Identifier":
+		var ji jsonIdentifier
+		if err := json.Unmarshal(data, &ji); err != nil {
+			return nil, err
+		}
+		return &Identifier{Token: fromJSONToken(ji.Token), Value: ji.Value}, nil
+
+	case "IntegerLiteral":
+		var jil jsonIntegerLiteral
+		if err := json.Unmarshal(data, &jil); err != nil {
+			return nil, err
+		}
+		return &IntegerLiteral{Token: fromJSONToken(jil.Token), Value: jil.Value}, nil
+
+	case "BooleanLiteral":
+		var jbl jsonBooleanLiteral
+		if err := json.Unmarshal(data, &jbl); err != nil {
+			return nil, err
+		}
+		return &BooleanLiteral{Token: fromJSONToken(jbl.Token), Value: jbl.Value}, nil
+
+	case "PrefixExpression":
+		var jpe jsonPrefixExpression
+		if err := json.Unmarshal(data, &jpe); err != nil {
+			return nil, err
+		}
+		right, err := unmarshalExpression(jpe.Right, "PrefixExpression", "right", required)
+		if err != nil {
+			return nil, err
+		}
+		return &PrefixExpression{Token: fromJSONToken(jpe.Token), Operator: jpe.Operator, Right: right}, nil
+
+	case "InfixExpression":
+		var jie jsonInfixExpression
+		if err := json.Unmarshal(data, &jie); err != nil {
+			return nil, err
+		}
+		left, err := unmarshalExpression(jie.Left, "InfixExpression", "left", required)
+		if err != nil {
+			return nil, err
+		}
+		right, err := unmarshalExpression(jie.Right, "InfixExpression", "right", required)
+		if err != nil {
+			return nil, err
+		}
+		return &InfixExpression{Token: fromJSONToken(jie.Token), Left: left, Operator: jie.Operator, Right: right}, nil
+
+	case "BlockStatement":
+		var jbs jsonBlockStatement
+		if err := json.Unmarshal(data, &jbs); err != nil {
+			return nil, err
+		}
+		statements, err := unmarshalStatements(jbs.Statements)
+		if err != nil {
+			return nil, err
+		}
+		return &BlockStatement{Token: fromJSONToken(jbs.Token), Statements: statements}, nil
+
+	case "WhileStatement":
+		var jws jsonWhileStatement
+		if err := json.Unmarshal(data, &jws); err != nil {
+			return nil, err
+		}
+		condition, err := unmarshalExpression(jws.Condition, "WhileStatement", "condition", required)
+		if err != nil {
+			return nil, err
+		}
+		body, err := unmarshalBlock(jws.Body, "WhileStatement", "body", required)
+		if err != nil {
+			return nil, err
+		}
+		return &WhileStatement{Token: fromJSONToken(jws.Token), Condition: condition, Body: body}, nil
+
+	case "ForInStatement":
+		var jfs jsonForInStatement
+		if err := json.Unmarshal(data, &jfs); err != nil {
+			return nil, err
+		}
+		variable, err := unmarshalIdentifier(jfs.Variable, "ForInStatement", "variable", required)
+		if err != nil {
+			return nil, err
+		}
+		iterable, err := unmarshalExpression(jfs.Iterable, "ForInStatement", "iterable", required)
+		if err != nil {
+			return nil, err
+		}
+		body, err := unmarshalBlock(jfs.Body, "ForInStatement", "body", required)
+		if err != nil {
+			return nil, err
+		}
+		return &ForInStatement{Token: fromJSONToken(jfs.Token), Variable: variable, Iterable: iterable, Body: body}, nil
+
+	case "BreakStatement":
+		var jto jsonTokenOnly
+		if err := json.Unmarshal(data, &jto); err != nil {
+			return nil, err
+		}
+		return &BreakStatement{Token: fromJSONToken(jto.Token)}, nil
+
+	case "ContinueStatement":
+		var jto jsonTokenOnly
+		if err := json.Unmarshal(data, &jto); err != nil {
+			return nil, err
+		}
+		return &ContinueStatement{Token: fromJSONToken(jto.Token)}, nil
+
+	case "AssignExpression":
+		var jae jsonAssignExpression
+		if err := json.Unmarshal(data, &jae); err != nil {
+			return nil, err
+		}
+		target, err := unmarshalIdentifier(jae.Target, "AssignExpression", "target", required)
+		if err != nil {
+			return nil, err
+		}
+		value, err := unmarshalExpression(jae.Value, "AssignExpression", "value", required)
+		if err != nil {
+			return nil, err
+		}
+		return &AssignExpression{Token: fromJSONToken(jae.Token), Target: target, Operator: jae.Operator, Value: value}, nil
+
+	case "StringLiteral":
+		var jsl jsonStringLiteral
+		if err := json.Unmarshal(data, &jsl); err != nil {
+			return nil, err
+		}
+		return &StringLiteral{Token: fromJSONToken(jsl.Token), Value: jsl.Value}, nil
+
+	case "ImportStatement":
+		var jis jsonImportStatement
+		if err := json.Unmarshal(data, &jis); err != nil {
+			return nil, err
+		}
+		path, err := unmarshalExpression(jis.Path, "ImportStatement", "path", required)
+		if err != nil {
+			return nil, err
+		}
+		str, ok := path.(*StringLiteral)
+		if !ok && path != nil {
+			return nil, fmt.Errorf("ast.UnmarshalJSON: %T is not a string literal", path)
+		}
+		name, err := unmarshalIdentifier(jis.Name, "ImportStatement", "name", required)
+		if err != nil {
+			return nil, err
+		}
+		return &ImportStatement{Token: fromJSONToken(jis.Token), Path: str, Name: name}, nil
+
+	case "ExportStatement":
+		var jes jsonExportStatement
+		if err := json.Unmarshal(data, &jes); err != nil {
+			return nil, err
+		}
+		statement, err := unmarshalStatement(jes.Statement, "ExportStatement", "statement", required)
+		if err != nil {
+			return nil, err
+		}
+		switch statement.(type) {
+		case *LetStatement, *ConstStatement:
+		default:
+			return nil, fmt.Errorf("ast.UnmarshalJSON: ExportStatement cannot export a %T", statement)
+		}
+		return &ExportStatement{Token: fromJSONToken(jes.Token), Statement: statement}, nil
+
+	case "ThrowStatement":
+		var jts jsonThrowStatement
+		if err := json.Unmarshal(data, &jts); err != nil {
+			return nil, err
+		}
+		value, err := unmarshalExpression(jts.Value, "ThrowStatement", "value", required)
+		if err != nil {
+			return nil, err
+		}
+		return &ThrowStatement{Token: fromJSONToken(jts.Token), Value: value}, nil
+
+	case "TryStatement":
+		var jts jsonTryStatement
+		if err := json.Unmarshal(data, &jts); err != nil {
+			return nil, err
+		}
+		body, err := unmarshalBlock(jts.Body, "TryStatement", "body", required)
+		if err != nil {
+			return nil, err
+		}
+		catchParam, err := unmarshalIdentifier(jts.CatchParam, "TryStatement", "catchParam", optional)
+		if err != nil {
+			return nil, err
+		}
+		catch, err := unmarshalBlock(jts.Catch, "TryStatement", "catch", optional)
+		if err != nil {
+			return nil, err
+		}
+		finally, err := unmarshalBlock(jts.Finally, "TryStatement", "finally", optional)
+		if err != nil {
+			return nil, err
+		}
+		if (catchParam == nil) != (catch == nil) {
+			return nil, fmt.Errorf(`ast.UnmarshalJSON: TryStatement needs both "catchParam" and "catch" or neither`)
+		}
+		if catch == nil && finally == nil {
+			return nil, fmt.Errorf(`ast.UnmarshalJSON: TryStatement needs "catch" or "finally"`)
+		}
+		return &TryStatement{Token: fromJSONToken(jts.Token), Body: body, CatchParam: catchParam, Catch: catch, Finally: finally}, nil
+
+	default:
+		return nil, fmt.Errorf("ast.UnmarshalJSON: unknown node type %q", header.Type)
+	}
+}
+
+// marshalChild encodes an optional child, using null when it is absent.
+func marshalChild(node Node) (json.RawMessage, error) {
+	if isNil(node) {
+		return json.RawMessage("null"), nil
+	}
+	return MarshalJSON(node)
+}
+
+func isNil(node Node) bool {
+	if node == nil {
+		return true
+	}
+	v := reflect.ValueOf(node)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+func marshalStatements(stmts []Statement) ([]json.RawMessage, error) {
+	raws := []json.RawMessage{}
+	for _, stmt := range stmts {
+		raw, err := MarshalJSON(stmt)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, raw)
+	}
+	return raws, nil
+}
+
+func unmarshalStatements(raws []json.RawMessage) ([]Statement, error) {
+	stmts := []Statement{}
+	for _, raw := range raws {
+		if isNull(raw) {
+			return nil, fmt.Errorf("ast.UnmarshalJSON: null statement in statement list")
+		}
+		stmt, err := unmarshalStatement(raw, "", "", optional)
+		if err != nil {
+			return nil, err
+		}
+		stmts = append(stmts, stmt)
+	}
+	return stmts, nil
+}
+
+// The unmarshal helpers decode member of a parent node. A null or absent
+// member decodes to nil if it is optional and is an error otherwise.
+
+func unmarshalStatement(raw json.RawMessage, parent, member string, opt bool) (Statement, error) {
+	if isNull(raw) {
+		return nil, missing(parent, member, opt)
+	}
+	node, err := UnmarshalJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	stmt, ok := node.(Statement)
+	if !ok {
+		return nil, fmt.Errorf("ast.UnmarshalJSON: %T is not a statement", node)
+	}
+	return stmt, nil
+}
+
+func unmarshalExpression(raw json.RawMessage, parent, member string, opt bool) (Expression, error) {
+	if isNull(raw) {
+		return nil, missing(parent, member, opt)
+	}
+	node, err := UnmarshalJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	exp, ok := node.(Expression)
+	if !ok {
+		return nil, fmt.Errorf("ast.UnmarshalJSON: %T is not an expression", node)
+	}
+	return exp, nil
+}
+
+func unmarshalIdentifier(raw json.RawMessage, parent, member string, opt bool) (*Identifier, error) {
+	exp, err := unmarshalExpression(raw, parent, member, opt)
+	if err != nil || exp == nil {
+		return nil, err
+	}
+	ident, ok := exp.(*Identifier)
+	if !ok {
+		return nil, fmt.Errorf("ast.UnmarshalJSON: %T is not an identifier", exp)
+	}
+	return ident, nil
+}
+
+func unmarshalAnnotation(raw json.RawMessage, parent, member string, opt bool) (*TypeAnnotation, error) {
+	if isNull(raw) {
+		return nil, missing(parent, member, opt)
+	}
+	node, err := UnmarshalJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	annotation, ok := node.(*TypeAnnotation)
+	if !ok {
+		return nil, fmt.Errorf("ast.UnmarshalJSON: %T is not a type annotation", node)
+	}
+	return annotation, nil
+}
+
+func unmarshalBlock(raw json.RawMessage, parent, member string, opt bool) (*BlockStatement, error) {
+	stmt, err := unmarshalStatement(raw, parent, member, opt)
+	if err != nil || stmt == nil {
+		return nil, err
+	}
+	block, ok := stmt.(*BlockStatement)
+	if !ok {
+		return nil, fmt.Errorf("ast.UnmarshalJSON: %T is not a block statement", stmt)
+	}
+	return block, nil
+}
+
+func missing(parent, member string, opt bool) error {
+	if opt {
+		return nil
+	}
+	return fmt.Errorf("ast.UnmarshalJSON: %s is missing its %q member", parent, member)
+}
+
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(raw) == "null"
+}
+
+func toJSONToken(t token.Token) jsonToken {
+	return jsonToken{Type: t.Type, Literal: t.Literal, Line: t.Pos.Line, Column: t.Pos.Column}
+}
+
+func fromJSONToken(jt jsonToken) token.Token {
+	return token.Token{
+		Type:    jt.Type,
+		Literal: jt.Literal,
+		Pos:     token.Position{Line: jt.Line, Column: jt.Column},
+	}
+}
@@ -0,0 +1,112 @@
+package ast
+
+import (
+	"../token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Token: token.Token{Type: token.LET, Literal: "let", Pos: token.Position{Line: 1, Column: 1}},
+				Identifier: &Identifier{
+					Token: token.Token{Type: token.IDENT, Literal: "x", Pos: token.Position{Line: 1, Column: 5}},
+					Value: "x",
+				},
+				Annotation: &TypeAnnotation{
+					Token: token.Token{Type: token.IDENT, Literal: "int", Pos: token.Position{Line: 1, Column: 8}},
+					Name:  "int",
+				},
+				Value: &PrefixExpression{
+					Token:    token.Token{Type: token.MINUS, Literal: "-", Pos: token.Position{Line: 1, Column: 9}},
+					Operator: "-",
+					Right: &IntegerLiteral{
+						Token: token.Token{Type: token.INT, Literal: "5", Pos: token.Position{Line: 1, Column: 10}},
+						Value: 5,
+					},
+				},
+			},
+			&ReturnStatement{Token: token.Token{Type: token.RETURN, Literal: "return", Pos: token.Position{Line: 2, Column: 1}}},
+			&ExpressionStatement{
+				Token: token.Token{Type: token.IDENT, Literal: "x", Pos: token.Position{Line: 3, Column: 1}},
+				Expression: &Identifier{
+					Token: token.Token{Type: token.IDENT, Literal: "x", Pos: token.Position{Line: 3, Column: 1}},
+					Value: "x",
+				},
+			},
+		},
+	}
+
+	data, err := MarshalJSON(program)
+	if err != nil {
+		t.Fatalf("MarshalJSON returned error %v\n", err)
+	}
+
+	node, err := UnmarshalJSON(data)
+	if err != nil {
+		t.Fatalf("UnmarshalJSON returned error %v\n", err)
+	}
+
+	if !reflect.DeepEqual(node, program) {
+		t.Fatalf("JSON round trip changed the tree.\nExpected = %#v\ngot      = %#v\n", program, node)
+	}
+
+	if node.String() != program.String() {
+		t.Fatalf("JSON round trip changed String(). Expected = %s, got = %s\n", program.String(), node.String())
+	}
+}
+
+func TestJSONCoversEveryNodeType(t *testing.T) {
+	for _, n := range allNodes {
+		data, err := MarshalJSON(n)
+		if err != nil {
+			t.Errorf("MarshalJSON(%T) returned error %v\n", n, err)
+			continue
+		}
+
+		// Zero-value nodes lack required children and may be rejected,
+		// but their type must be known.
+		if _, err := UnmarshalJSON(data); err != nil && strings.Contains(err.Error(), "unknown node type") {
+			t.Errorf("UnmarshalJSON(%s) returned error %v\n", data, err)
+		}
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	tt := []struct {
+		input string
+		err   string
+	}{
+		{`{"type": "Nope"}`, `unknown node type "Nope"`},
+		{`{"type": "Program", "statements": [{"type": "Identifier"}]}`, "is not a statement"},
+		{`{"type": "ExpressionStatement", "expression": {"type": "Program"}}`, "is not an expression"},
+		{`[1, 2]`, "cannot unmarshal"},
+		{`{"type": "ThrowStatement", "value": null}`, `ThrowStatement is missing its "value" member`},
+		{`{"type": "WhileStatement", "body": {"type": "BlockStatement", "statements": []}}`, `WhileStatement is missing its "condition" member`},
+		{`{"type": "WhileStatement", "condition": {"type": "Identifier", "value": "x"}}`, `WhileStatement is missing its "body" member`},
+		{`{"type": "ExportStatement", "statement": null}`, `ExportStatement is missing its "statement" member`},
+		{`{"type": "LetStatement", "value": {"type": "IntegerLiteral", "value": 1}}`, `LetStatement is missing its "identifier" member`},
+		{`{"type": "ExpressionStatement"}`, `ExpressionStatement is missing its "expression" member`},
+		{`{"type": "Program", "statements": [null]}`, "null statement in statement list"},
+		{`{"type": "ExportStatement", "statement": {"type": "BlockStatement", "statements": []}}`, "ExportStatement cannot export a *ast.BlockStatement"},
+		{
+			`{"type": "TryStatement", "body": {"type": "BlockStatement", "statements": []}}`,
+			`TryStatement needs "catch" or "finally"`,
+		},
+		{
+			`{"type": "TryStatement", "body": {"type": "BlockStatement", "statements": []}, "catch": {"type": "BlockStatement", "statements": []}}`,
+			`TryStatement needs both "catchParam" and "catch" or neither`,
+		},
+	}
+
+	for _, tc := range tt {
+		_, err := UnmarshalJSON([]byte(tc.input))
+
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("UnmarshalJSON(%s) should fail with %q. Got = %v\n", tc.input, tc.err, err)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package ast
+
+import "fmt"
+
+// ModifierFunc is called by Modify for every node in a tree. The node it
+// returns replaces the node it was given.
+type ModifierFunc func(Node) Node
+
+// Modify rewrites the tree rooted at node bottom-up: the children of a node
+// are modified before modifier is called on the node itself, so modifier
+// always sees children that have already been rewritten. Nodes are updated
+// in place and the (possibly replaced) root is returned.
+//
+// Modify panics if modifier replaces a child with a node of a type its field
+// can't hold, or with nil where the child is required. Only optional children
+// (type annotations, return values and the clauses of a try statement) may
+// be cleared this way. A statement for which modifier returns nil is removed
+// from its enclosing list.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch n := node.(type) {
+	case *Program:
+		n.Statements = modifyStatements(n.Statements, modifier)
+
+	case *LetStatement:
+		n.Identifier = modifyIdentifier(n, "Identifier", n.Identifier, required, modifier)
+		n.Annotation = modifyAnnotation(n, "Annotation", n.Annotation, optional, modifier)
+		n.Value = modifyExpression(n, "Value", n.Value, required, modifier)
+
+	case *ConstStatement:
+		n.Identifier = modifyIdentifier(n, "Identifier", n.Identifier, required, modifier)
+		n.Annotation = modifyAnnotation(n, "Annotation", n.Annotation, optional, modifier)
+		n.Value = modifyExpression(n, "Value", n.Value, required, modifier)
+
+	case *ReturnStatement:
+		n.ReturnValue = modifyExpression(n, "ReturnValue", n.ReturnValue, optional, modifier)
+
+	case *ExpressionStatement:
+		n.Expression = modifyExpression(n, "Expression", n.Expression, required, modifier)
+
+	case *Identifier, *IntegerLiteral, *BooleanLiteral, *StringLiteral:
+		// nothing to do
+
+	case *PrefixExpression:
+		n.Right = modifyExpression(n, "Right", n.Right, required, modifier)
+
+	case *InfixExpression:
+		n.Left = modifyExpression(n, "Left", n.Left, required, modifier)
+		n.Right = modifyExpression(n, "Right", n.Right, required, modifier)
+
+	case *BlockStatement:
+		n.Statements = modifyStatements(n.Statements, modifier)
+
+	case *WhileStatement:
+		n.Condition = modifyExpression(n, "Condition", n.Condition, required, modifier)
+		n.Body = modifyBlock(n, "Body", n.Body, required, modifier)
+
+	case *ForInStatement:
+		n.Variable = modifyIdentifier(n, "Variable", n.Variable, required, modifier)
+		n.Iterable = modifyExpression(n, "Iterable", n.Iterable, required, modifier)
+		n.Body = modifyBlock(n, "Body", n.Body, required, modifier)
+
+	case *BreakStatement, *ContinueStatement, *TypeAnnotation:
+		// nothing to do
+
+	case *AssignExpression:
+		n.Target = modifyIdentifier(n, "Target", n.Target, required, modifier)
+		n.Value = modifyExpression(n, "Value", n.Value, required, modifier)
+
+	case *ImportStatement:
+		n.Path = modifyStringLiteral(n, "Path", n.Path, required, modifier)
+		n.Name = modifyIdentifier(n, "Name", n.Name, required, modifier)
+
+	case *ExportStatement:
+		n.Statement = modifyStatement(n, "Statement", n.Statement, required, modifier)
+
+	case *ThrowStatement:
+		n.Value = modifyExpression(n, "Value", n.Value, required, modifier)
+
+	case *TryStatement:
+		n.Body = modifyBlock(n, "Body", n.Body, required, modifier)
+		n.CatchParam = modifyIdentifier(n, "CatchParam", n.CatchParam, optional, modifier)
+		n.Catch = modifyBlock(n, "Catch", n.Catch, optional, modifier)
+		n.Finally = modifyBlock(n, "Finally", n.Finally, optional, modifier)
+
+	default:
+		panic(fmt.Sprintf("ast.Modify: unexpected node type %T", n))
+	}
+
+	return modifier(node)
+}
+
+func modifyStatements(stmts []Statement, modifier ModifierFunc) []Statement {
+	modified := stmts[:0]
+
+	for _, stmt := range stmts {
+		m := Modify(stmt, modifier)
+		if isNil(m) {
+			continue
+		}
+		s, ok := m.(Statement)
+		if !ok {
+			panic(fmt.Sprintf("ast.Modify: modifier replaced a statement with %T, which is not a Statement", m))
+		}
+		modified = append(modified, s)
+	}
+
+	return modified
+}
+
+// Whether a child of a node may be cleared by the modifier.
+const (
+	required = false
+	optional = true
+)
+
+// modifyChild modifies child, the field of parent with the given name, and
+// returns its replacement, or nil if an optional child was cleared.
+func modifyChild(parent Node, field string, child Node, opt bool, modifier ModifierFunc) Node {
+	m := Modify(child, modifier)
+	if isNil(m) {
+		if !opt {
+			panic(fmt.Sprintf("ast.Modify: modifier cleared the required %s of %T", field, parent))
+		}
+		return nil
+	}
+	return m
+}
+
+func mismatch(parent Node, field string, m Node, want string) string {
+	return fmt.Sprintf("ast.Modify: modifier replaced the %s of %T with %T, which is not %s", field, parent, m, want)
+}
+
+func modifyExpression(parent Node, field string, exp Expression, opt bool, modifier ModifierFunc) Expression {
+	if exp == nil {
+		return nil
+	}
+	m := modifyChild(parent, field, exp, opt, modifier)
+	if m == nil {
+		return nil
+	}
+	e, ok := m.(Expression)
+	if !ok {
+		panic(mismatch(parent, field, m, "an Expression"))
+	}
+	return e
+}
+
+func modifyStatement(parent Node, field string, stmt Statement, opt bool, modifier ModifierFunc) Statement {
+	if stmt == nil {
+		return nil
+	}
+	m := modifyChild(parent, field, stmt, opt, modifier)
+	if m == nil {
+		return nil
+	}
+	s, ok := m.(Statement)
+	if !ok {
+		panic(mismatch(parent, field, m, "a Statement"))
+	}
+	return s
+}
+
+func modifyIdentifier(parent Node, field string, ident *Identifier, opt bool, modifier ModifierFunc) *Identifier {
+	if ident == nil {
+		return nil
+	}
+	m := modifyChild(parent, field, ident, opt, modifier)
+	if m == nil {
+		return nil
+	}
+	i, ok := m.(*Identifier)
+	if !ok {
+		panic(mismatch(parent, field, m, "an *ast.Identifier"))
+	}
+	return i
+}
+
+func modifyBlock(parent Node, field string, block *BlockStatement, opt bool, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+	m := modifyChild(parent, field, block, opt, modifier)
+	if m == nil {
+		return nil
+	}
+	b, ok := m.(*BlockStatement)
+	if !ok {
+		panic(mismatch(parent, field, m, "an *ast.BlockStatement"))
+	}
+	return b
+}
+
+func modifyAnnotation(parent Node, field string, annotation *TypeAnnotation, opt bool, modifier ModifierFunc) *TypeAnnotation {
+	if annotation == nil {
+		return nil
+	}
+	m := modifyChild(parent, field, annotation, opt, modifier)
+	if m == nil {
+		return nil
+	}
+	a, ok := m.(*TypeAnnotation)
+	if !ok {
+		panic(mismatch(parent, field, m, "an *ast.TypeAnnotation"))
+	}
+	return a
+}
+
+func modifyStringLiteral(parent Node, field string, sl *StringLiteral, opt bool, modifier ModifierFunc) *StringLiteral {
+	if sl == nil {
+		return nil
+	}
+	m := modifyChild(parent, field, sl, opt, modifier)
+	if m == nil {
+		return nil
+	}
+	l, ok := m.(*StringLiteral)
+	if !ok {
+		panic(mismatch(parent, field, m, "an *ast.StringLiteral"))
+	}
+	return l
+}
@@ -0,0 +1,176 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestModify(t *testing.T) {
+	one := func() Expression { return &IntegerLiteral{Value: 1} }
+	two := func() Expression { return &IntegerLiteral{Value: 2} }
+
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok || integer.Value != 1 {
+			return node
+		}
+		return &IntegerLiteral{Value: 2}
+	}
+
+	tt := []struct {
+		input    Node
+		expected Node
+	}{
+		{one(), two()},
+		{
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			&Program{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+		},
+		{
+			&LetStatement{Identifier: &Identifier{Value: "x"}, Value: one()},
+			&LetStatement{Identifier: &Identifier{Value: "x"}, Value: two()},
+		},
+		{
+			&ReturnStatement{ReturnValue: one()},
+			&ReturnStatement{ReturnValue: two()},
+		},
+		{
+			&PrefixExpression{Operator: "-", Right: one()},
+			&PrefixExpression{Operator: "-", Right: two()},
+		},
+	}
+
+	for _, tc := range tt {
+		modified := Modify(tc.input, turnOneIntoTwo)
+
+		if !reflect.DeepEqual(modified, tc.expected) {
+			t.Errorf("Modify produced the wrong tree. Expected = %#v, got = %#v\n", tc.expected, modified)
+		}
+	}
+}
+
+func TestModifyIsBottomUp(t *testing.T) {
+	// --1 is folded one level at a time, which only works if the inner
+	// prefix expression has been rewritten before the outer one is seen.
+	node := &PrefixExpression{
+		Operator: "-",
+		Right:    &PrefixExpression{Operator: "-", Right: &IntegerLiteral{Value: 1}},
+	}
+
+	fold := func(node Node) Node {
+		prefix, ok := node.(*PrefixExpression)
+		if !ok || prefix.Operator != "-" {
+			return node
+		}
+		integer, ok := prefix.Right.(*IntegerLiteral)
+		if !ok {
+			return node
+		}
+		return &IntegerLiteral{Value: -integer.Value}
+	}
+
+	modified := Modify(node, fold)
+
+	if !reflect.DeepEqual(modified, &IntegerLiteral{Value: 1}) {
+		t.Fatalf("Expected --1 to fold to 1. Got = %#v\n", modified)
+	}
+}
+
+func TestModifyRemovesNilStatements(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{Expression: &Identifier{Value: "keep"}},
+			&ReturnStatement{},
+			&ExpressionStatement{Expression: &Identifier{Value: "alsoKeep"}},
+		},
+	}
+
+	Modify(program, func(node Node) Node {
+		if _, ok := node.(*ReturnStatement); ok {
+			return nil
+		}
+		return node
+	})
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("Expected the return statement to be removed. Got = %d statements\n", len(program.Statements))
+	}
+}
+
+func TestModifyCoversEveryNodeType(t *testing.T) {
+	for _, n := range allNodes {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("Modify does not support %T: %v\n", n, r)
+				}
+			}()
+			Modify(n, func(node Node) Node { return node })
+		}()
+	}
+}
+
+func TestModifyPanicsOnInvalidReplacement(t *testing.T) {
+	tt := []struct {
+		node     Node
+		modifier ModifierFunc
+		panic    string
+	}{
+		{
+			&LetStatement{Identifier: &Identifier{Value: "x"}, Value: &IntegerLiteral{Value: 1}},
+			func(node Node) Node {
+				if _, ok := node.(*Identifier); ok {
+					return &IntegerLiteral{Value: 2}
+				}
+				return node
+			},
+			"ast.Modify: modifier replaced the Identifier of *ast.LetStatement with *ast.IntegerLiteral, which is not an *ast.Identifier",
+		},
+		{
+			&PrefixExpression{Operator: "-", Right: &IntegerLiteral{Value: 1}},
+			func(node Node) Node {
+				if _, ok := node.(*IntegerLiteral); ok {
+					return nil
+				}
+				return node
+			},
+			"ast.Modify: modifier cleared the required Right of *ast.PrefixExpression",
+		},
+		{
+			&BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: &Identifier{Value: "x"}}}},
+			func(node Node) Node {
+				if _, ok := node.(*ExpressionStatement); ok {
+					return &Identifier{Value: "x"}
+				}
+				return node
+			},
+			"ast.Modify: modifier replaced a statement with *ast.Identifier, which is not a Statement",
+		},
+	}
+
+	for _, tc := range tt {
+		func() {
+			defer func() {
+				if r := recover(); r != tc.panic {
+					t.Errorf("Modify(%s) panicked with %v, expected %q\n", tc.node, r, tc.panic)
+				}
+			}()
+			Modify(tc.node, tc.modifier)
+		}()
+	}
+}
+
+func TestModifyClearsOptionalChildren(t *testing.T) {
+	node := &ReturnStatement{ReturnValue: &IntegerLiteral{Value: 1}}
+
+	Modify(node, func(node Node) Node {
+		if _, ok := node.(*IntegerLiteral); ok {
+			return nil
+		}
+		return node
+	})
+
+	if node.ReturnValue != nil {
+		t.Fatalf("Expected the return value to be cleared. Got = %#v\n", node.ReturnValue)
+	}
+}
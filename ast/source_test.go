@@ -0,0 +1,98 @@
+package ast_test
+
+import (
+	"../ast"
+	"../lexer"
+	"../parser"
+	"reflect"
+	"testing"
+)
+
+// sources exercise every child of every statement and expression through
+// real parser output rather than hand-built trees. Each puts the literal 1
+// in every child that can hold an expression.
+var sources = []string{
+	"let x = -1;",
+	"return 1;",
+	"1 + 1 * (1 - 1) / 1 < 1 == 1 != 1 > 1;",
+	"while (1) { 1; };",
+	"while (true) { 1; break; continue; }",
+	"for (x in 1) { 1; }",
+	"let x = 0; x = 1; x += 1;",
+	"const x = -1;",
+	"let x: int = 1;",
+	"import \"lib/util\"; export let x = 1; export const y = 1;",
+	"throw 1;",
+	"try { 1; } catch (e) { 1; } finally { 1; }",
+	"try { 1; } finally { 1; }",
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) != 0 {
+		t.Fatalf("parser errors for %q: %v\n", input, errs)
+	}
+	return program
+}
+
+func countIntegers(node ast.Node, value int) int {
+	n := 0
+	ast.Inspect(node, func(node ast.Node) bool {
+		if integer, ok := node.(*ast.IntegerLiteral); ok && integer.Value == value {
+			n++
+		}
+		return true
+	})
+	return n
+}
+
+func TestModifyRewritesParsedSource(t *testing.T) {
+	turnOneIntoTwo := func(node ast.Node) ast.Node {
+		integer, ok := node.(*ast.IntegerLiteral)
+		if !ok || integer.Value != 1 {
+			return node
+		}
+		return &ast.IntegerLiteral{Token: integer.Token, Value: 2}
+	}
+
+	for _, input := range sources {
+		program := parse(t, input)
+		ones := countIntegers(program, 1)
+		if ones == 0 {
+			t.Fatalf("%q contains no literal 1 to rewrite\n", input)
+		}
+
+		ast.Modify(program, turnOneIntoTwo)
+
+		if n := countIntegers(program, 1); n != 0 {
+			t.Errorf("Modify left %d literals unrewritten in %q: %s\n", n, input, program)
+		}
+		if n := countIntegers(program, 2); n != ones {
+			t.Errorf("Modify rewrote %d literals in %q, expected %d: %s\n", n, input, ones, program)
+		}
+	}
+}
+
+func TestJSONRoundTripsParsedSource(t *testing.T) {
+	for _, input := range sources {
+		program := parse(t, input)
+
+		data, err := ast.MarshalJSON(program)
+		if err != nil {
+			t.Fatalf("MarshalJSON(%q) returned error %v\n", input, err)
+		}
+
+		node, err := ast.UnmarshalJSON(data)
+		if err != nil {
+			t.Fatalf("UnmarshalJSON(%s) returned error %v\n", data, err)
+		}
+
+		if !reflect.DeepEqual(node, program) {
+			t.Errorf("JSON round trip changed the tree for %q.\nExpected = %#v\ngot      = %#v\n", input, program, node)
+		}
+		if node.String() != program.String() {
+			t.Errorf("JSON round trip changed String() for %q. Expected = %s, got = %s\n", input, program.String(), node.String())
+		}
+	}
+}
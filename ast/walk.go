@@ -0,0 +1,186 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses an AST in depth-first order: It starts by calling
+// v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor
+// w for each of the non-nil children of node, followed by a call of
+// w.Visit(nil).
+//
+// Children are visited in source order:
+//
+//	*Program             Statements
+//	*LetStatement        Identifier, Annotation, Value
+//	*ConstStatement      Identifier, Annotation, Value
+//	*ReturnStatement     ReturnValue
+//	*ExpressionStatement Expression
+//	*PrefixExpression    Right
+//	*InfixExpression     Left, Right
+//	*BlockStatement      Statements
+//	*WhileStatement      Condition, Body
+//	*ForInStatement      Variable, Iterable, Body
+//	*AssignExpression    Target, Value
+//	*ImportStatement     Path, Name
+//	*ExportStatement     Statement
+//	*ThrowStatement      Value
+//	*TryStatement        Body, CatchParam, Catch, Finally
+//
+// Identifier, IntegerLiteral, BooleanLiteral, StringLiteral,
+// BreakStatement, ContinueStatement and TypeAnnotation have no children.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, stmt := range n.Statements {
+			Walk(v, stmt)
+		}
+
+	case *LetStatement:
+		if n.Identifier != nil {
+			Walk(v, n.Identifier)
+		}
+		if n.Annotation != nil {
+			Walk(v, n.Annotation)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ConstStatement:
+		if n.Identifier != nil {
+			Walk(v, n.Identifier)
+		}
+		if n.Annotation != nil {
+			Walk(v, n.Annotation)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ReturnStatement:
+		if n.ReturnValue != nil {
+			Walk(v, n.ReturnValue)
+		}
+
+	case *ExpressionStatement:
+		if n.Expression != nil {
+			Walk(v, n.Expression)
+		}
+
+	case *Identifier, *IntegerLiteral, *BooleanLiteral, *StringLiteral:
+		// nothing to do
+
+	case *PrefixExpression:
+		if n.Right != nil {
+			Walk(v, n.Right)
+		}
+
+	case *InfixExpression:
+		if n.Left != nil {
+			Walk(v, n.Left)
+		}
+		if n.Right != nil {
+			Walk(v, n.Right)
+		}
+
+	case *BlockStatement:
+		for _, stmt := range n.Statements {
+			Walk(v, stmt)
+		}
+
+	case *WhileStatement:
+		if n.Condition != nil {
+			Walk(v, n.Condition)
+		}
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+
+	case *ForInStatement:
+		if n.Variable != nil {
+			Walk(v, n.Variable)
+		}
+		if n.Iterable != nil {
+			Walk(v, n.Iterable)
+		}
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+
+	case *BreakStatement, *ContinueStatement, *TypeAnnotation:
+		// nothing to do
+
+	case *AssignExpression:
+		if n.Target != nil {
+			Walk(v, n.Target)
+		}
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *ImportStatement:
+		if n.Path != nil {
+			Walk(v, n.Path)
+		}
+		if n.Name != nil {
+			Walk(v, n.Name)
+		}
+
+	case *ExportStatement:
+		if n.Statement != nil {
+			Walk(v, n.Statement)
+		}
+
+	case *ThrowStatement:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+
+	case *TryStatement:
+		if n.Body != nil {
+			Walk(v, n.Body)
+		}
+		if n.CatchParam != nil {
+			Walk(v, n.CatchParam)
+		}
+		if n.Catch != nil {
+			Walk(v, n.Catch)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses an AST in depth-first order: It starts by calling
+// f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the non-nil children of node, followed by a
+// call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,177 @@
+package ast
+
+import (
+	"../token"
+	"fmt"
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// allNodes holds a zero value of every node type in this package. When a
+// new node type is added it has to be listed here, which in turn requires
+// Walk and Modify to handle it.
+var allNodes = []Node{
+	&Program{},
+	&LetStatement{},
+	&ConstStatement{},
+	&ReturnStatement{},
+	&ExpressionStatement{},
+	&Identifier{},
+	&IntegerLiteral{},
+	&BooleanLiteral{},
+	&PrefixExpression{},
+	&InfixExpression{},
+	&BlockStatement{},
+	&WhileStatement{},
+	&ForInStatement{},
+	&BreakStatement{},
+	&ContinueStatement{},
+	&AssignExpression{},
+	&TypeAnnotation{},
+	&StringLiteral{},
+	&ImportStatement{},
+	&ExportStatement{},
+	&ThrowStatement{},
+	&TryStatement{},
+}
+
+func TestWalkOrder(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&LetStatement{
+				Token:      token.NewToken(token.LET, "let"),
+				Identifier: &Identifier{Token: token.NewToken(token.IDENT, "x"), Value: "x"},
+				Value: &PrefixExpression{
+					Token:    token.NewToken(token.MINUS, "-"),
+					Operator: "-",
+					Right:    &IntegerLiteral{Token: token.NewToken(token.INT, "5"), Value: 5},
+				},
+			},
+			&ReturnStatement{
+				Token:       token.NewToken(token.RETURN, "return"),
+				ReturnValue: &Identifier{Token: token.NewToken(token.IDENT, "x"), Value: "x"},
+			},
+			&ExpressionStatement{
+				Token:      token.NewToken(token.IDENT, "y"),
+				Expression: &Identifier{Token: token.NewToken(token.IDENT, "y"), Value: "y"},
+			},
+		},
+	}
+
+	var visited []string
+	Inspect(program, func(n Node) bool {
+		if n == nil {
+			visited = append(visited, "end")
+		} else {
+			visited = append(visited, fmt.Sprintf("%T", n))
+		}
+		return true
+	})
+
+	expected := []string{
+		"*ast.Program",
+		"*ast.LetStatement",
+		"*ast.Identifier", "end",
+		"*ast.PrefixExpression",
+		"*ast.IntegerLiteral", "end",
+		"end",
+		"end",
+		"*ast.ReturnStatement",
+		"*ast.Identifier", "end",
+		"end",
+		"*ast.ExpressionStatement",
+		"*ast.Identifier", "end",
+		"end",
+		"end",
+	}
+
+	if !reflect.DeepEqual(visited, expected) {
+		t.Fatalf("Walk visited nodes in the wrong order.\nExpected = %v\ngot      = %v\n", expected, visited)
+	}
+}
+
+func TestInspectPrunes(t *testing.T) {
+	program := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{
+				Expression: &PrefixExpression{
+					Operator: "!",
+					Right:    &IntegerLiteral{Value: 5},
+				},
+			},
+		},
+	}
+
+	count := 0
+	Inspect(program, func(n Node) bool {
+		if n != nil {
+			count++
+		}
+		_, isPrefix := n.(*PrefixExpression)
+		return !isPrefix
+	})
+
+	if count != 3 {
+		t.Fatalf("Expected Inspect to visit 3 nodes when pruning at the prefix expression. Got = %d\n", count)
+	}
+}
+
+func TestWalkCoversEveryNodeType(t *testing.T) {
+	listed := map[string]bool{}
+	for _, n := range allNodes {
+		listed[reflect.TypeOf(n).Elem().Name()] = true
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("Walk does not support %T: %v\n", n, r)
+				}
+			}()
+			Inspect(n, func(Node) bool { return true })
+		}()
+	}
+
+	for _, name := range declaredNodeTypes(t) {
+		if !listed[name] {
+			t.Errorf("node type %s is missing from allNodes\n", name)
+		}
+	}
+}
+
+// declaredNodeTypes returns the names of all types in this package that
+// declare a TokenLiteral method, i.e. every type implementing Node.
+func declaredNodeTypes(t *testing.T) []string {
+	fset := gotoken.NewFileSet()
+	notTest := func(fi os.FileInfo) bool { return !strings.HasSuffix(fi.Name(), "_test.go") }
+
+	pkgs, err := goparser.ParseDir(fset, ".", notTest, 0)
+	if err != nil {
+		t.Fatalf("could not parse package ast: %v\n", err)
+	}
+
+	var names []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*goast.FuncDecl)
+				if !ok || fn.Recv == nil || fn.Name.Name != "TokenLiteral" {
+					continue
+				}
+				recv := fn.Recv.List[0].Type
+				if star, ok := recv.(*goast.StarExpr); ok {
+					recv = star.X
+				}
+				names = append(names, recv.(*goast.Ident).Name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"./lexer"
+	"./parser"
+	"./printer"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const sourceExt = ".mk"
+
+type formatter struct {
+	list  bool
+	write bool
+	diff  bool
+
+	exitCode int
+}
+
+// formatCmd implements monkey fmt. Like gofmt, it formats stdin when given
+// no paths, walks directories for .mk files, and with -l, -w or -d lists,
+// rewrites or diffs files whose formatting differs instead of printing them.
+func formatCmd(args []string) int {
+	f := &formatter{}
+
+	flags := flag.NewFlagSet("fmt", flag.ContinueOnError)
+	flags.BoolVar(&f.list, "l", false, "list files whose formatting differs from monkey fmt's")
+	flags.BoolVar(&f.write, "w", false, "write result to (source) file instead of stdout")
+	flags.BoolVar(&f.diff, "d", false, "display diffs instead of rewriting files")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: monkey fmt [flags] [path ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if flags.NArg() == 0 {
+		if f.write {
+			fmt.Fprintln(os.Stderr, "monkey fmt: cannot use -w with standard input")
+			return exitUsage
+		}
+		f.processFile("<standard input>", os.Stdin, os.Stdout)
+		return f.exitCode
+	}
+
+	for _, path := range flags.Args() {
+		info, err := os.Stat(path)
+		if err != nil {
+			f.report(err, exitUsage)
+		} else if info.IsDir() {
+			f.walkDir(path)
+		} else {
+			f.processFile(path, nil, os.Stdout)
+		}
+	}
+
+	return f.exitCode
+}
+
+func (f *formatter) walkDir(root string) {
+	_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			f.report(err, exitUsage)
+		} else if !info.IsDir() && strings.HasSuffix(info.Name(), sourceExt) {
+			f.processFile(path, nil, os.Stdout)
+		}
+		return nil
+	})
+}
+
+// processFile formats the file at filename, reading it from in if in is not
+// nil.
+func (f *formatter) processFile(filename string, in io.Reader, out io.Writer) {
+	if in == nil {
+		file, err := os.Open(filename)
+		if err != nil {
+			f.report(err, exitUsage)
+			return
+		}
+		defer file.Close()
+		in = file
+	}
+
+	src, err := ioutil.ReadAll(in)
+	if err != nil {
+		f.report(err, exitUsage)
+		return
+	}
+
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) != 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s:%s", filename, err)
+		}
+		f.exitCode = exitParseError
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.Write(shebang(src))
+	if err := printer.Fprint(&buf, program); err != nil {
+		f.report(err, exitParseError)
+		return
+	}
+	res := buf.Bytes()
+
+	if !f.list && !f.write && !f.diff {
+		_, _ = out.Write(res)
+		return
+	}
+	if bytes.Equal(src, res) {
+		return
+	}
+
+	if f.list {
+		fmt.Fprintln(out, filename)
+	}
+	if f.write {
+		// Keep the file's permissions, as gofmt does.
+		info, err := os.Stat(filename)
+		if err != nil {
+			f.report(err, exitUsage)
+			return
+		}
+		if err := ioutil.WriteFile(filename, res, info.Mode().Perm()); err != nil {
+			f.report(err, exitUsage)
+			return
+		}
+	}
+	if f.diff {
+		d, err := diff(src, res)
+		if err != nil {
+			f.report(fmt.Errorf("computing diff: %v", err), exitUsage)
+			return
+		}
+		fmt.Fprintf(out, "diff %s monkeyfmt/%s\n", filename, filename)
+		_, _ = out.Write(d)
+	}
+}
+
+// shebang returns the leading "#!" line of src, which the lexer skips, so
+// that it can be written back unchanged. The line always ends in a newline.
+func shebang(src []byte) []byte {
+	if !bytes.HasPrefix(src, []byte("#!")) {
+		return nil
+	}
+	if i := bytes.IndexByte(src, '\n'); i >= 0 {
+		return src[:i+1]
+	}
+	return append(append([]byte{}, src...), '\n')
+}
+
+func (f *formatter) report(err error, exitCode int) {
+	fmt.Fprintf(os.Stderr, "monkey fmt: %v\n", err)
+	f.exitCode = exitCode
+}
+
+// diff shells out to diff -u, as gofmt originally did.
+func diff(b1, b2 []byte) ([]byte, error) {
+	f1, err := writeTempFile("", "monkeyfmt", b1)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f1)
+
+	f2, err := writeTempFile("", "monkeyfmt", b2)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f2)
+
+	data, err := exec.Command("diff", "-u", f1, f2).CombinedOutput()
+	if len(data) > 0 {
+		// diff exits with a non-zero status when the files don't match.
+		// Ignore that failure as long as we get output.
+		return data, nil
+	}
+	return data, err
+}
+
+func writeTempFile(dir, prefix string, data []byte) (string, error) {
+	file, err := ioutil.TempFile(dir, prefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = file.Write(data)
+	if err1 := file.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFormatKeepsShebang(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected string
+	}{
+		{"#!/usr/bin/env monkey\nlet   x=5;", "#!/usr/bin/env monkey\nlet x = 5;\n"},
+		{"#!/usr/bin/env monkey\nlet x = 5;\n", "#!/usr/bin/env monkey\nlet x = 5;\n"},
+		{"#!/usr/bin/env monkey", "#!/usr/bin/env monkey\n"},
+	}
+
+	for _, tc := range tt {
+		var out bytes.Buffer
+		f := &formatter{}
+		f.processFile("script.mk", strings.NewReader(tc.input), &out)
+
+		if f.exitCode != exitOK || out.String() != tc.expected {
+			t.Errorf("fmt %q wrong. Expected = %q, got = %q (exit code %d)\n", tc.input, tc.expected, out.String(), f.exitCode)
+		}
+	}
+
+	var out bytes.Buffer
+	f := &formatter{list: true}
+	f.processFile("script.mk", strings.NewReader("#!/usr/bin/env monkey\nlet x = 5;\n"), &out)
+	if out.Len() != 0 {
+		t.Errorf("fmt -l lists a formatted shebang script. Got = %q\n", out.String())
+	}
+}
+
+func TestFormatWriteKeepsFileMode(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "script.mk")
+	if err := os.WriteFile(path, []byte("let   x=5;"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// WriteFile is subject to the umask; read back the mode it really got.
+	before, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &formatter{write: true}
+	f.processFile(path, nil, ioutil.Discard)
+	if f.exitCode != exitOK {
+		t.Fatalf("fmt -w %s exited with %d\n", path, f.exitCode)
+	}
+
+	after, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Mode() != before.Mode() {
+		t.Errorf("fmt -w changed the file mode from %v to %v\n", before.Mode(), after.Mode())
+	}
+	if src, _ := os.ReadFile(path); string(src) != "let x = 5;\n" {
+		t.Errorf("fmt -w wrote %q\n", src)
+	}
+}
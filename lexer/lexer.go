@@ -10,11 +10,14 @@ type Lexer struct {
 	currIdx int
 	nextIdx int
 	ch      byte
+	line    int
+	column  int
 }
 
 func New(input string) *Lexer {
-	l := Lexer{input, 0, 0, 0}
+	l := Lexer{input: input, line: 1}
 	l.readChar()
+	l.skipShebang()
 
 	return &l
 }
@@ -23,6 +26,7 @@ func (l *Lexer) NextToken() token.Token {
 	var currTok token.Token
 
 	l.skipWhitespace()
+	pos := token.Position{Line: l.line, Column: l.column}
 
 	switch l.ch {
 	case '(':
@@ -37,6 +41,8 @@ func (l *Lexer) NextToken() token.Token {
 		currTok = token.NewToken(token.SEMICOLON, ";")
 	case ',':
 		currTok = token.NewToken(token.COMMA, ",")
+	case ':':
+		currTok = token.NewToken(token.COLON, ":")
 	case '=':
 		nextChar := l.peekNextChar()
 		if nextChar == '=' {
@@ -46,13 +52,33 @@ func (l *Lexer) NextToken() token.Token {
 			currTok = token.NewToken(token.ASSIGN, "=")
 		}
 	case '+':
-		currTok = token.NewToken(token.PLUS, "+")
+		if l.peekNextChar() == '=' {
+			currTok = token.NewToken(token.PLUS_ASSIGN, "+=")
+			l.readChar()
+		} else {
+			currTok = token.NewToken(token.PLUS, "+")
+		}
 	case '-':
-		currTok = token.NewToken(token.MINUS, "-")
+		if l.peekNextChar() == '=' {
+			currTok = token.NewToken(token.MINUS_ASSIGN, "-=")
+			l.readChar()
+		} else {
+			currTok = token.NewToken(token.MINUS, "-")
+		}
 	case '*':
-		currTok = token.NewToken(token.ASTERISK, "*")
+		if l.peekNextChar() == '=' {
+			currTok = token.NewToken(token.ASTERISK_ASSIGN, "*=")
+			l.readChar()
+		} else {
+			currTok = token.NewToken(token.ASTERISK, "*")
+		}
 	case '/':
-		currTok = token.NewToken(token.BACKSLASH, "/")
+		if l.peekNextChar() == '=' {
+			currTok = token.NewToken(token.BACKSLASH_ASSIGN, "/=")
+			l.readChar()
+		} else {
+			currTok = token.NewToken(token.BACKSLASH, "/")
+		}
 	case '!':
 		nextChar := l.peekNextChar()
 		if nextChar == '=' {
@@ -65,6 +91,13 @@ func (l *Lexer) NextToken() token.Token {
 		currTok = token.NewToken(token.GT, ">")
 	case '<':
 		currTok = token.NewToken(token.LT, "<")
+	case '"':
+		literal, ok := l.readString()
+		if ok {
+			currTok = token.NewToken(token.STRING, literal)
+		} else {
+			currTok = token.NewToken(token.ILLEGAL, "\""+literal)
+		}
 	case 0:
 		currTok = token.NewToken(token.EOF, "")
 	default:
@@ -74,16 +107,25 @@ func (l *Lexer) NextToken() token.Token {
 		} else if isInt(l.ch) {
 			currTok = token.NewToken(token.INT, l.readInt())
 		} else {
-			currTok = token.NewToken(token.ILLEGAL, "")
+			currTok = token.NewToken(token.ILLEGAL, string(l.ch))
+			l.readChar()
 		}
+		currTok.Pos = pos
 		return currTok
 	}
 	l.readChar()
+	currTok.Pos = pos
 
 	return currTok
 }
 
 func (l *Lexer) readChar() {
+	if l.ch == '\n' {
+		l.line++
+		l.column = 0
+	}
+	l.column++
+
 	if l.nextIdx >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -102,7 +144,7 @@ func (l *Lexer) peekNextChar() byte {
 
 func (l *Lexer) readIdentifier() string {
 	startIdx := l.currIdx
-	for isLetter(l.input[l.currIdx]) {
+	for isLetter(l.ch) {
 		l.readChar()
 	}
 	return l.input[startIdx:l.currIdx]
@@ -111,13 +153,41 @@ func (l *Lexer) readIdentifier() string {
 func (l *Lexer) readInt() string {
 	startIdx := l.currIdx
 
-	for isInt(l.input[l.currIdx]) {
+	for isInt(l.ch) {
 		l.readChar()
 	}
 
 	return l.input[startIdx:l.currIdx]
 }
 
+// readString reads the contents of a string literal, leaving l.ch on the
+// closing quote. It reports false if the input ends before the literal is
+// closed.
+func (l *Lexer) readString() (string, bool) {
+	startIdx := l.currIdx + 1
+
+	for {
+		l.readChar()
+		if l.ch == '"' {
+			return l.input[startIdx:l.currIdx], true
+		}
+		if l.ch == 0 {
+			return l.input[startIdx:l.currIdx], false
+		}
+	}
+}
+
+// skipShebang skips a leading "#!" line so that scripts can be executed
+// directly, e.g. with #!/usr/bin/env monkey.
+func (l *Lexer) skipShebang() {
+	if l.ch != '#' || l.peekNextChar() != '!' {
+		return
+	}
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
 		l.readChar()
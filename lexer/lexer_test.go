@@ -28,80 +28,80 @@ if (5 < 10) {
 `
 
 	expectedTokens := []token.Token{
-		{token.LET, "let"},
-		{token.IDENT, "five"},
-		{token.ASSIGN, "="},
-		{token.INT, "5"},
-		{token.SEMICOLON, ";"},
-		{token.LET, "let"},
-		{token.IDENT, "ten"},
-		{token.ASSIGN, "="},
-		{token.INT, "10"},
-		{token.SEMICOLON, ";"},
-		{token.LET, "let"},
-		{token.IDENT, "add"},
-		{token.ASSIGN, "="},
-		{token.FUNCTION, "fn"},
-		{token.LPAREN, "("},
-		{token.IDENT, "x"},
-		{token.COMMA, ","},
-		{token.IDENT, "y"},
-		{token.RPAREN, ")"},
-		{token.LBRACE, "{"},
-		{token.IDENT, "x"},
-		{token.PLUS, "+"},
-		{token.IDENT, "y"},
-		{token.SEMICOLON, ";"},
-		{token.RBRACE, "}"},
-		{token.SEMICOLON, ";"},
-		{token.LET, "let"},
-		{token.IDENT, "result"},
-		{token.ASSIGN, "="},
-		{token.IDENT, "add"},
-		{token.LPAREN, "("},
-		{token.IDENT, "five"},
-		{token.COMMA, ","},
-		{token.IDENT, "ten"},
-		{token.RPAREN, ")"},
-		{token.SEMICOLON, ";"},
-		{token.BANG, "!"},
-		{token.MINUS, "-"},
-		{token.BACKSLASH, "/"},
-		{token.ASTERISK, "*"},
-		{token.INT, "5"},
-		{token.SEMICOLON, ";"},
-		{token.INT, "5"},
-		{token.LT, "<"},
-		{token.INT, "10"},
-		{token.GT, ">"},
-		{token.INT, "5"},
-		{token.SEMICOLON, ";"},
-		{token.IF, "if"},
-		{token.LPAREN, "("},
-		{token.INT, "5"},
-		{token.LT, "<"},
-		{token.INT, "10"},
-		{token.RPAREN, ")"},
-		{token.LBRACE, "{"},
-		{token.RETURN, "return"},
-		{token.TRUE, "true"},
-		{token.SEMICOLON, ";"},
-		{token.RBRACE, "}"},
-		{token.ELSE, "else"},
-		{token.LBRACE, "{"},
-		{token.RETURN, "return"},
-		{token.FALSE, "false"},
-		{token.SEMICOLON, ";"},
-		{token.RBRACE, "}"},
-		{token.INT, "10"},
-		{token.EQ, "=="},
-		{token.INT, "10"},
-		{token.SEMICOLON, ";"},
-		{token.INT, "10"},
-		{token.NEQ, "!="},
-		{token.INT, "9"},
-		{token.SEMICOLON, ";"},
-		{token.EOF, ""},
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "five"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "ten"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.INT, Literal: "10"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "add"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.FUNCTION, Literal: "fn"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.IDENT, Literal: "y"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.PLUS, Literal: "+"},
+		{Type: token.IDENT, Literal: "y"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "result"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.IDENT, Literal: "add"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.IDENT, Literal: "five"},
+		{Type: token.COMMA, Literal: ","},
+		{Type: token.IDENT, Literal: "ten"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.BANG, Literal: "!"},
+		{Type: token.MINUS, Literal: "-"},
+		{Type: token.BACKSLASH, Literal: "/"},
+		{Type: token.ASTERISK, Literal: "*"},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.LT, Literal: "<"},
+		{Type: token.INT, Literal: "10"},
+		{Type: token.GT, Literal: ">"},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.IF, Literal: "if"},
+		{Type: token.LPAREN, Literal: "("},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.LT, Literal: "<"},
+		{Type: token.INT, Literal: "10"},
+		{Type: token.RPAREN, Literal: ")"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.RETURN, Literal: "return"},
+		{Type: token.TRUE, Literal: "true"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.ELSE, Literal: "else"},
+		{Type: token.LBRACE, Literal: "{"},
+		{Type: token.RETURN, Literal: "return"},
+		{Type: token.FALSE, Literal: "false"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.RBRACE, Literal: "}"},
+		{Type: token.INT, Literal: "10"},
+		{Type: token.EQ, Literal: "=="},
+		{Type: token.INT, Literal: "10"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.INT, Literal: "10"},
+		{Type: token.NEQ, Literal: "!="},
+		{Type: token.INT, Literal: "9"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.EOF, Literal: ""},
 	}
 
 	lexer := New(input)
@@ -118,3 +118,105 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestNextTokenSkipsShebang(t *testing.T) {
+	input := "#!/usr/bin/env monkey\nlet x = 5;"
+
+	expectedTokens := []token.Token{
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "x"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.INT, Literal: "5"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lexer := New(input)
+
+	for i, et := range expectedTokens {
+		currTok := lexer.NextToken()
+
+		if currTok.Type != et.Type || currTok.Literal != et.Literal {
+			t.Fatalf("expectedTokens[%d]: Expected=%v, got=%v\n", i, et, currTok)
+		}
+	}
+}
+
+func TestNextTokenPositions(t *testing.T) {
+	input := "#!/usr/bin/env monkey\nlet x = 5;\n\n  -foo;"
+
+	expectedPositions := []token.Position{
+		{Line: 2, Column: 1},
+		{Line: 2, Column: 5},
+		{Line: 2, Column: 7},
+		{Line: 2, Column: 9},
+		{Line: 2, Column: 10},
+		{Line: 4, Column: 3},
+		{Line: 4, Column: 4},
+		{Line: 4, Column: 7},
+		{Line: 4, Column: 8},
+	}
+
+	lexer := New(input)
+
+	for i, ep := range expectedPositions {
+		currTok := lexer.NextToken()
+
+		if currTok.Pos != ep {
+			t.Fatalf("expectedPositions[%d]: incorrect position for %v. Expected=%v, got=%v\n", i, currTok, ep, currTok.Pos)
+		}
+	}
+}
+
+func TestNextTokenAssignmentOperators(t *testing.T) {
+	input := "x = 1; x += 2; x -= 3; x *= 4; x /= 5; -=-"
+
+	expectedTypes := []token.TokenType{
+		token.IDENT, token.ASSIGN, token.INT, token.SEMICOLON,
+		token.IDENT, token.PLUS_ASSIGN, token.INT, token.SEMICOLON,
+		token.IDENT, token.MINUS_ASSIGN, token.INT, token.SEMICOLON,
+		token.IDENT, token.ASTERISK_ASSIGN, token.INT, token.SEMICOLON,
+		token.IDENT, token.BACKSLASH_ASSIGN, token.INT, token.SEMICOLON,
+		token.MINUS_ASSIGN, token.MINUS,
+		token.EOF,
+	}
+
+	lexer := New(input)
+
+	for i, et := range expectedTypes {
+		currTok := lexer.NextToken()
+
+		if currTok.Type != et {
+			t.Fatalf("expectedTypes[%d]: incorrect TokenType. Expected=%v, got=%v\n", i, et, currTok.Type)
+		}
+	}
+}
+
+func TestNextTokenStrings(t *testing.T) {
+	input := `import "lib/strings"; export let s = "";
+"unterminated`
+
+	expectedTokens := []token.Token{
+		{Type: token.IMPORT, Literal: "import"},
+		{Type: token.STRING, Literal: "lib/strings"},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.EXPORT, Literal: "export"},
+		{Type: token.LET, Literal: "let"},
+		{Type: token.IDENT, Literal: "s"},
+		{Type: token.ASSIGN, Literal: "="},
+		{Type: token.STRING, Literal: ""},
+		{Type: token.SEMICOLON, Literal: ";"},
+		{Type: token.ILLEGAL, Literal: "\"unterminated"},
+		{Type: token.EOF, Literal: ""},
+	}
+
+	lexer := New(input)
+
+	for i, et := range expectedTokens {
+		currTok := lexer.NextToken()
+
+		if currTok.Type != et.Type || currTok.Literal != et.Literal {
+			t.Fatalf("expectedTokens[%d]: Expected=%v, got=%v\n", i, et, currTok)
+		}
+	}
+}
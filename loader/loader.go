@@ -0,0 +1,223 @@
+// Package loader finds, parses and caches Monkey modules along with the
+// modules they import. Modules are read from one or more roots, each an
+// fs.FS, so they can come from disk (os.DirFS) or be embedded in a Go
+// binary (embed.FS).
+package loader
+
+import (
+	"../ast"
+	"../lexer"
+	"../parser"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+const sourceExt = ".mk"
+
+// Root is a file system modules are loaded from.
+type Root struct {
+	FS fs.FS
+	// Dir is the slash-separated name of the directory FS reads, such as
+	// the directory given to os.DirFS. It prefixes the paths of the modules
+	// loaded from the root, which identify them, so two roots should only
+	// share a Dir if they read the same directory. It may be empty.
+	Dir string
+}
+
+// Module is a parsed source file.
+type Module struct {
+	// Path is the module's cleaned, slash-separated path in its root,
+	// joined to the root's Dir.
+	Path    string
+	Program *ast.Program
+	// Imports are the modules this module imports, in source order.
+	Imports []*Module
+	// Exports are the names bound by the module's export statements.
+	Exports []string
+
+	root Root
+	// name is the module's path within root.FS.
+	name string
+}
+
+// ParseError reports the syntax errors in one module.
+type ParseError struct {
+	Path   string
+	Errors []string
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	for i, err := range e.Errors {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(e.Path + ":" + strings.TrimSuffix(err, "\n"))
+	}
+	return sb.String()
+}
+
+// CycleError reports a chain of imports that leads back to its start.
+type CycleError struct {
+	Cycle []string
+}
+
+func (e *CycleError) Error() string {
+	return "import cycle: " + strings.Join(e.Cycle, " -> ")
+}
+
+type Loader struct {
+	root       Root
+	searchPath []Root
+
+	modules map[string]*Module
+	order   []*Module
+	// loading is the chain of modules currently being loaded, used to
+	// detect import cycles.
+	loading []string
+}
+
+// New returns a Loader that loads the modules it is asked for from root.
+// Import paths starting with ./ or ../ are resolved against the importing
+// module's directory, in the importing module's root; any other import path
+// is looked up in each root of searchPath in turn. The .mk extension may be
+// left off.
+func New(root Root, searchPath ...Root) *Loader {
+	return &Loader{
+		root:       root,
+		searchPath: searchPath,
+		modules:    make(map[string]*Module),
+	}
+}
+
+// Load loads the module at name, a path in the loader's root, and
+// everything it imports. Each module is parsed once; loading it again
+// returns the cached Module.
+func (l *Loader) Load(name string) (*Module, error) {
+	return l.load(l.root, path.Clean(name), nil)
+}
+
+// LoadSource loads a module whose source is given directly rather than read
+// from the file system, such as an expression passed on the command line.
+// Its imports are resolved as if it were stored at name.
+func (l *Loader) LoadSource(name, src string) (*Module, error) {
+	return l.load(l.root, path.Clean(name), &src)
+}
+
+// Modules returns every module loaded so far, each one after the modules it
+// imports.
+func (l *Loader) Modules() []*Module {
+	return l.order
+}
+
+func (l *Loader) load(root Root, name string, src *string) (*Module, error) {
+	modPath := path.Join(root.Dir, name)
+	if mod, ok := l.modules[modPath]; ok {
+		return mod, nil
+	}
+
+	for i, loading := range l.loading {
+		if loading == modPath {
+			cycle := append(append([]string{}, l.loading[i:]...), modPath)
+			return nil, &CycleError{Cycle: cycle}
+		}
+	}
+	l.loading = append(l.loading, modPath)
+	defer func() { l.loading = l.loading[:len(l.loading)-1] }()
+
+	if src == nil {
+		data, err := fs.ReadFile(root.FS, name)
+		if err != nil {
+			return nil, err
+		}
+		s := string(data)
+		src = &s
+	}
+
+	p := parser.New(lexer.New(*src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) != 0 {
+		return nil, &ParseError{Path: modPath, Errors: errs}
+	}
+
+	mod := &Module{Path: modPath, Program: program, root: root, name: name}
+
+	var err error
+	ast.Inspect(program, func(n ast.Node) bool {
+		if err != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *ast.ImportStatement:
+			var imported *Module
+			if imported, err = l.importModule(mod, n); err == nil {
+				mod.Imports = append(mod.Imports, imported)
+			}
+			return false
+		case *ast.ExportStatement:
+			if ident := exportedName(n); ident != nil {
+				mod.Exports = append(mod.Exports, ident.Value)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.modules[modPath] = mod
+	l.order = append(l.order, mod)
+
+	return mod, nil
+}
+
+func (l *Loader) importModule(importer *Module, is *ast.ImportStatement) (*Module, error) {
+	root, name, err := l.resolve(importer, is.Path.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s:%s: %v", importer.Path, is.Path.Token.Pos, err)
+	}
+	return l.load(root, name, nil)
+}
+
+// resolve finds the root and the path within it of the file that an import
+// path in the module importer refers to.
+func (l *Loader) resolve(importer *Module, importPath string) (Root, string, error) {
+	if path.Ext(importPath) != sourceExt {
+		importPath += sourceExt
+	}
+
+	if strings.HasPrefix(importPath, "./") || strings.HasPrefix(importPath, "../") {
+		name := path.Join(path.Dir(importer.name), importPath)
+		if !fs.ValidPath(name) {
+			return Root{}, "", fmt.Errorf("import %q is outside the module root", importPath)
+		}
+		if _, err := fs.Stat(importer.root.FS, name); err == nil {
+			return importer.root, name, nil
+		}
+		return Root{}, "", fmt.Errorf("cannot find module %q", importPath)
+	}
+
+	name := path.Clean(importPath)
+	if !fs.ValidPath(name) {
+		return Root{}, "", fmt.Errorf("import %q is outside the search path", importPath)
+	}
+	for _, root := range l.searchPath {
+		if _, err := fs.Stat(root.FS, name); err == nil {
+			return root, name, nil
+		}
+	}
+
+	return Root{}, "", fmt.Errorf("cannot find module %q", importPath)
+}
+
+func exportedName(es *ast.ExportStatement) *ast.Identifier {
+	switch s := es.Statement.(type) {
+	case *ast.LetStatement:
+		return s.Identifier
+	case *ast.ConstStatement:
+		return s.Identifier
+	}
+	return nil
+}
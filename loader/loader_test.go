@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"main.mk":        {Data: []byte(`import "lib/strings"; import "./util"; export let x = 1;`)},
+		"util.mk":        {Data: []byte(`import "lib/strings"; export const limit = 10; let hidden = 2;`)},
+		"lib/strings.mk": {Data: []byte(`export let sep = ",";`)},
+	}
+	std := fstest.MapFS{
+		"lib/strings.mk": {Data: []byte(`export let sep = ",";`)},
+	}
+
+	l := New(Root{FS: fsys}, Root{FS: fstest.MapFS{}, Dir: "vendor"}, Root{FS: std, Dir: "std"})
+
+	mod, err := l.Load("main.mk")
+	if err != nil {
+		t.Fatalf("Load returned error %v\n", err)
+	}
+
+	if len(mod.Imports) != 2 || mod.Imports[0].Path != "std/lib/strings.mk" || mod.Imports[1].Path != "util.mk" {
+		t.Fatalf("main.mk imports are wrong. Got = %v\n", paths(mod.Imports))
+	}
+
+	if mod.Imports[0] != mod.Imports[1].Imports[0] {
+		t.Errorf("lib/strings was loaded twice instead of being cached\n")
+	}
+
+	if strings.Join(mod.Imports[1].Exports, ",") != "limit" {
+		t.Errorf("util.mk exports are wrong. Got = %v\n", mod.Imports[1].Exports)
+	}
+
+	if got := strings.Join(paths(l.Modules()), " "); got != "std/lib/strings.mk util.mk main.mk" {
+		t.Errorf("Modules() is not in dependency order. Got = %s\n", got)
+	}
+
+	again, err := l.Load("./main.mk")
+	if err != nil || again != mod {
+		t.Errorf("loading main.mk again did not return the cached module. Got = %v, %v\n", again, err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.mk":       {Data: []byte(`import "./b";`)},
+		"b.mk":       {Data: []byte(`import "./dir/c.mk";`)},
+		"dir/c.mk":   {Data: []byte(`import "../a";`)},
+		"missing.mk": {Data: []byte("\nimport \"nowhere\";")},
+		"escape.mk":  {Data: []byte(`import "../../etc/passwd";`)},
+		"broken.mk":  {Data: []byte(`let = 5;`)},
+		"climb.mk":   {Data: []byte(`import "lib/../../x";`)},
+	}
+
+	tt := []struct {
+		name string
+		err  string
+	}{
+		{"a.mk", "import cycle: a.mk -> b.mk -> dir/c.mk -> a.mk"},
+		{"missing.mk", `missing.mk:2:8: cannot find module "nowhere.mk"`},
+		{"escape.mk", `escape.mk:1:8: import "../../etc/passwd.mk" is outside the module root`},
+		{"climb.mk", `climb.mk:1:8: import "lib/../../x.mk" is outside the search path`},
+		{"broken.mk", "broken.mk:1:5: expected next token of type IDENT but got ASSIGN instead"},
+		{"absent.mk", "absent.mk"},
+	}
+
+	for _, tc := range tt {
+		_, err := New(Root{FS: fsys}, Root{FS: fsys}).Load(tc.name)
+
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("Load(%s) should fail with %q. Got = %v\n", tc.name, tc.err, err)
+		}
+	}
+}
+
+func TestLoadSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"scripts/util.mk": {Data: []byte(`export let x = 1;`)},
+	}
+
+	mod, err := New(Root{FS: fsys}).LoadSource("scripts/-e", `import "./util";`)
+	if err != nil {
+		t.Fatalf("LoadSource returned error %v\n", err)
+	}
+
+	if len(mod.Imports) != 1 || mod.Imports[0].Path != "scripts/util.mk" {
+		t.Fatalf("imports are wrong. Got = %v\n", paths(mod.Imports))
+	}
+}
+
+func TestLoadFromSeveralRoots(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/main.mk":          {Data: []byte(`import "../shared/a"; import "util";`)},
+		"shared/a.mk":          {Data: []byte(`import "./b";`)},
+		"shared/b.mk":          {Data: []byte(`export let b = 1;`)},
+		"usr/lib/util.mk":      {Data: []byte(`import "./helpers/h"; import "shared/b";`)},
+		"usr/lib/helpers/h.mk": {Data: []byte(`export let h = 1;`)},
+	}
+	lib, err := fs.Sub(fsys, "usr/lib")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l := New(Root{FS: fsys, Dir: "/"}, Root{FS: lib, Dir: "/usr/lib"}, Root{FS: fsys, Dir: "/"})
+
+	if _, err := l.Load("app/main.mk"); err != nil {
+		t.Fatalf("Load returned error %v\n", err)
+	}
+
+	expected := "/shared/b.mk /shared/a.mk /usr/lib/helpers/h.mk /usr/lib/util.mk /app/main.mk"
+	if got := strings.Join(paths(l.Modules()), " "); got != expected {
+		t.Errorf("modules are wrong. Expected = %s, got = %s\n", expected, got)
+	}
+
+	_, err = New(Root{FS: fsys, Dir: "/"}).LoadSource("app/-e", `import "../../x";`)
+	if err == nil || !strings.Contains(err.Error(), `/app/-e:1:8: import "../../x.mk" is outside the module root`) {
+		t.Errorf("an import leaving the root should fail. Got = %v\n", err)
+	}
+}
+
+func paths(mods []*Module) []string {
+	var ps []string
+	for _, mod := range mods {
+		ps = append(ps, mod.Path)
+	}
+	return ps
+}
@@ -1,14 +1,222 @@
 package main
 
 import (
-	"fmt"
+	"./ast"
+	"./loader"
 	"./repl"
+	"./resolver"
+	"./types"
+	"fmt"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Exit codes returned by the monkey command.
+const (
+	exitOK = iota
+	exitRuntimeError
+	exitParseError
+	exitUsage
 )
 
+// searchPathEnv names the environment variable listing the directories in
+// which non-relative imports are looked up.
+const searchPathEnv = "MONKEYPATH"
+
+const usage = `usage:
+	monkey                          start the REPL
+	monkey repl                     start the REPL
+	monkey run script.mk [args...]  run a script
+	monkey -e 'expr'                run an expression
+	monkey fmt [-l] [-w] [-d] [path ...]
+	                                format source files
+	monkey parse [-json] [file.mk]  print the parsed program
+
+Imports starting with ./ or ../ are relative to the directory of the
+importing module. Other imports are looked up in each directory listed in
+$MONKEYPATH (separated like $PATH, relative to the working directory), or in
+the script's directory if $MONKEYPATH is unset. An expression given with -e
+is a module in the working directory.
+
+exit codes: 0 success, 1 runtime error, 2 parse, name resolution or type
+error, 3 usage error
+`
+
 func main() {
-	fmt.Println("Welcome to the Monkey REPL!")
-	fmt.Println("You know what to do, don't you?")
+	os.Exit(dispatch(os.Args[1:]))
+}
+
+func dispatch(args []string) int {
+	if len(args) == 0 {
+		return startRepl()
+	}
+
+	switch args[0] {
+	case "repl":
+		if len(args) != 1 {
+			return usageError()
+		}
+		return startRepl()
+	case "run":
+		if len(args) < 2 {
+			return usageError()
+		}
+		// Script arguments are accepted so that shebang scripts can be
+		// invoked with them, but nothing can read them until there is an
+		// evaluator.
+		return runFile(args[1])
+	case "fmt":
+		return formatCmd(args[1:])
+	case "parse":
+		return parseCmd(args[1:])
+	case "-e":
+		if len(args) != 2 {
+			return usageError()
+		}
+		return runExpr(args[1])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return exitOK
+	default:
+		return usageError()
+	}
+}
+
+func startRepl() int {
+	if isTerminal(os.Stdin) {
+		fmt.Println("Welcome to the Monkey REPL!")
+		fmt.Println("You know what to do, don't you?")
+	}
 
 	repl.Start(os.Stdin, os.Stdout)
-}
\ No newline at end of file
+	return exitOK
+}
+
+// runFile runs the script at path. Imports starting with ./ or ../ are
+// resolved against the real directory of the importing module, any other
+// import through $MONKEYPATH.
+func runFile(path string) int {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey: %v\n", err)
+		return exitUsage
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey: %v\n", err)
+		return exitUsage
+	}
+	root, name := volumeRoot(abs)
+
+	searchPath, err := moduleSearchPath(dirRoot(filepath.Dir(abs)))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey: %v\n", err)
+		return exitUsage
+	}
+	return runSource(loader.New(root, searchPath...), name, string(src))
+}
+
+// runExpr runs expr as if it were a module in the working directory.
+func runExpr(expr string) int {
+	wd, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey: %v\n", err)
+		return exitUsage
+	}
+	root := loader.Root{FS: os.DirFS(wd)}
+
+	searchPath, err := moduleSearchPath(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey: %v\n", err)
+		return exitUsage
+	}
+	return runSource(loader.New(root, searchPath...), "-e", expr)
+}
+
+// volumeRoot returns a root reading the whole volume that the file at abs is
+// on, so that relative imports can reach any directory, and the file's path
+// within it.
+func volumeRoot(abs string) (loader.Root, string) {
+	vol := filepath.VolumeName(abs) + string(filepath.Separator)
+	name := strings.TrimPrefix(abs, vol)
+	return dirRoot(vol), filepath.ToSlash(name)
+}
+
+// dirRoot returns a root reading the directory dir, an absolute path.
+func dirRoot(dir string) loader.Root {
+	return loader.Root{FS: os.DirFS(dir), Dir: filepath.ToSlash(dir)}
+}
+
+// moduleSearchPath returns a root for each directory listed in $MONKEYPATH,
+// or just def if $MONKEYPATH is unset.
+func moduleSearchPath(def loader.Root) ([]loader.Root, error) {
+	var searchPath []loader.Root
+	for _, dir := range filepath.SplitList(os.Getenv(searchPathEnv)) {
+		if dir == "" {
+			continue
+		}
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, err
+		}
+		searchPath = append(searchPath, dirRoot(abs))
+	}
+	if len(searchPath) == 0 {
+		return []loader.Root{def}, nil
+	}
+	return searchPath, nil
+}
+
+// runSource loads src as the module name, along with everything it imports,
+// then resolves and type checks each module. Load, name resolution and type
+// errors fail like syntax errors; warnings are printed but don't. There is
+// no evaluator yet, so a program that passes all of these exits with exitOK.
+func runSource(l *loader.Loader, name, src string) int {
+	if _, err := l.LoadSource(name, src); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitParseError
+	}
+
+	for _, mod := range l.Modules() {
+		if code := check(mod.Path, mod.Program); code != exitOK {
+			return code
+		}
+	}
+
+	return exitOK
+}
+
+func check(name string, program *ast.Program) int {
+	result := resolver.Resolve(program)
+	for _, d := range result.Diagnostics {
+		fmt.Fprintf(os.Stderr, "%s:%s\n", name, d)
+	}
+	if result.HasErrors() {
+		return exitParseError
+	}
+
+	if _, errs := types.Check(program, result); len(errs) != 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s:%s\n", name, err)
+		}
+		return exitParseError
+	}
+
+	return exitOK
+}
+
+func usageError() int {
+	fmt.Fprint(os.Stderr, usage)
+	return exitUsage
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
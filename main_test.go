@@ -0,0 +1,99 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRunFileResolvesImports(t *testing.T) {
+	dir := t.TempDir()
+	system := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/main.mk":       "import \"../lib/util\";\nimport \"strings\";\nimport \"math\";\n",
+		"lib/util.mk":       "export let x = 1;\n",
+		"vendor/strings.mk": "export let y = 2;\n",
+	})
+	writeFiles(t, system, map[string]string{
+		"math.mk":            "import \"./internal/consts\";\nexport let z = 3;\n",
+		"internal/consts.mk": "export const pi = 3;\n",
+	})
+	t.Chdir(dir)
+	t.Setenv(searchPathEnv, "vendor"+string(os.PathListSeparator)+system)
+
+	if code := runFile("app/main.mk"); code != exitOK {
+		t.Errorf("runFile(app/main.mk) = %d, expected %d\n", code, exitOK)
+	}
+
+	t.Chdir(filepath.Join(dir, "vendor"))
+	t.Setenv(searchPathEnv, "."+string(os.PathListSeparator)+system)
+
+	if code := runFile(filepath.Join("..", "app", "main.mk")); code != exitOK {
+		t.Errorf("runFile(../app/main.mk) = %d, expected %d\n", code, exitOK)
+	}
+}
+
+func TestRunFileSearchesTheScriptDirectoryByDefault(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"app/main.mk":   "import \"helper\";\n",
+		"app/helper.mk": "export let x = 1;\n",
+		"other/main.mk": "import \"helper\";\n",
+	})
+	t.Chdir(t.TempDir())
+	t.Setenv(searchPathEnv, "")
+
+	if code := runFile(filepath.Join(dir, "app", "main.mk")); code != exitOK {
+		t.Errorf("runFile(app/main.mk) = %d, expected %d\n", code, exitOK)
+	}
+	if code := runFile(filepath.Join(dir, "other", "main.mk")); code != exitParseError {
+		t.Errorf("runFile(other/main.mk) = %d, expected %d\n", code, exitParseError)
+	}
+}
+
+func TestModuleSearchPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	def := dirRoot(filepath.Join(dir, "script"))
+
+	tt := []struct {
+		env      string
+		expected []string
+	}{
+		{env: "", expected: []string{def.Dir}},
+		{env: "lib" + string(os.PathListSeparator) + "vendor/", expected: []string{filepath.Join(dir, "lib"), filepath.Join(dir, "vendor")}},
+		{env: "../elsewhere", expected: []string{filepath.Join(filepath.Dir(dir), "elsewhere")}},
+		{env: filepath.Join(dir, "lib"), expected: []string{filepath.Join(dir, "lib")}},
+	}
+
+	for _, tc := range tt {
+		t.Setenv(searchPathEnv, tc.env)
+
+		searchPath, err := moduleSearchPath(def)
+		if err != nil {
+			t.Errorf("moduleSearchPath with %s=%q returned error %v\n", searchPathEnv, tc.env, err)
+			continue
+		}
+
+		var dirs []string
+		for _, root := range searchPath {
+			dirs = append(dirs, filepath.FromSlash(root.Dir))
+		}
+		if strings.Join(dirs, " ") != strings.Join(tc.expected, " ") {
+			t.Errorf("moduleSearchPath with %s=%q = %q, expected %q\n", searchPathEnv, tc.env, dirs, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"./ast"
+	"./lexer"
+	"./parser"
+	"./printer"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// parseCmd implements monkey parse, which prints the program parsed from a
+// file (or stdin) either as canonical source or, with -json, as its AST in
+// the schema documented by ast.MarshalJSON.
+func parseCmd(args []string) int {
+	flags := flag.NewFlagSet("parse", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print the AST as JSON")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: monkey parse [-json] [file.mk]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil || flags.NArg() > 1 {
+		return exitUsage
+	}
+
+	name, src, err := readSource(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey parse: %v\n", err)
+		return exitUsage
+	}
+
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) != 0 {
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "%s:%s", name, err)
+		}
+		return exitParseError
+	}
+
+	if !*asJSON {
+		if err := printer.Fprint(os.Stdout, program); err != nil {
+			fmt.Fprintf(os.Stderr, "monkey parse: %v\n", err)
+			return exitParseError
+		}
+		return exitOK
+	}
+
+	data, err := ast.MarshalJSON(program)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "monkey parse: %v\n", err)
+		return exitParseError
+	}
+	var out bytes.Buffer
+	_ = json.Indent(&out, data, "", "  ")
+	out.WriteString("\n")
+	_, _ = out.WriteTo(os.Stdout)
+
+	return exitOK
+}
+
+// readSource reads the file at path, or stdin if path is empty, and returns
+// the name to report errors against along with its contents.
+func readSource(path string) (string, string, error) {
+	if path == "" {
+		src, err := ioutil.ReadAll(os.Stdin)
+		return "<standard input>", string(src), err
+	}
+	src, err := ioutil.ReadFile(path)
+	return path, string(src), err
+}
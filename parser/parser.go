@@ -5,12 +5,15 @@ import (
 	"../lexer"
 	"../token"
 	"fmt"
+	"path"
 	"strconv"
+	"strings"
 )
 
 const (
 	_ int = iota
 	LOWEST
+	ASSIGNMENT
 	EQUALS
 	LESSGREATER
 	SUM
@@ -19,6 +22,22 @@ const (
 	CALL
 )
 
+var precedences = map[token.TokenType]int{
+	token.ASSIGN:           ASSIGNMENT,
+	token.PLUS_ASSIGN:      ASSIGNMENT,
+	token.MINUS_ASSIGN:     ASSIGNMENT,
+	token.ASTERISK_ASSIGN:  ASSIGNMENT,
+	token.BACKSLASH_ASSIGN: ASSIGNMENT,
+	token.EQ:               EQUALS,
+	token.NEQ:              EQUALS,
+	token.LT:               LESSGREATER,
+	token.GT:               LESSGREATER,
+	token.PLUS:             SUM,
+	token.MINUS:            SUM,
+	token.ASTERISK:         PRODUCT,
+	token.BACKSLASH:        PRODUCT,
+}
+
 type (
 	prefixParseFn func() ast.Expression
 	infixParseFn  func(expression ast.Expression) ast.Expression
@@ -31,6 +50,13 @@ type Parser struct {
 	errors         []string
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// loopDepth counts the loops enclosing the current token, so that break
+	// and continue can be rejected outside of them.
+	loopDepth int
+	// blockDepth counts the blocks enclosing the current token, so that
+	// export can be rejected outside of the top level.
+	blockDepth int
 }
 
 func New(l *lexer.Lexer) *Parser {
@@ -41,8 +67,27 @@ func New(l *lexer.Lexer) *Parser {
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefixParseFn(token.IDENT, p.parseIdentifier)
 	p.registerPrefixParseFn(token.INT, p.parseIntegerLiteral)
+	p.registerPrefixParseFn(token.STRING, p.parseStringLiteral)
+	p.registerPrefixParseFn(token.TRUE, p.parseBooleanLiteral)
+	p.registerPrefixParseFn(token.FALSE, p.parseBooleanLiteral)
 	p.registerPrefixParseFn(token.BANG, p.parsePrefixExpression)
 	p.registerPrefixParseFn(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefixParseFn(token.LPAREN, p.parseGroupedExpression)
+
+	p.infixParseFns = make(map[token.TokenType]infixParseFn)
+	p.registerInfixParseFn(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfixParseFn(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfixParseFn(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfixParseFn(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfixParseFn(token.BACKSLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfixParseFn(token.EQ, p.parseInfixExpression)
+	p.registerInfixParseFn(token.NEQ, p.parseInfixExpression)
+	p.registerInfixParseFn(token.LT, p.parseInfixExpression)
+	p.registerInfixParseFn(token.GT, p.parseInfixExpression)
+	p.registerInfixParseFn(token.PLUS, p.parseInfixExpression)
+	p.registerInfixParseFn(token.MINUS, p.parseInfixExpression)
+	p.registerInfixParseFn(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfixParseFn(token.BACKSLASH, p.parseInfixExpression)
 	return p
 }
 
@@ -71,11 +116,29 @@ func (p *Parser) nextToken() {
 }
 
 func (p *Parser) parseStatement() ast.Statement {
-	switch p.currToken.Literal {
-	case "let":
+	switch p.currToken.Type {
+	case token.LET:
 		return p.parseLetStatement()
-	case "return":
+	case token.CONST:
+		return p.parseConstStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
+	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForInStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.TRY:
+		return p.parseTryStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -88,28 +151,325 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		//	TODO: handle syntax error
 		return nil
 	}
-	ls.Identifier = &ast.Identifier{Token: token.NewToken(token.IDENT, "ident"), Value: p.currToken.Literal}
+	ls.Identifier = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+	ls.Annotation = p.parseTypeAnnotation()
 
 	if !p.eat(token.ASSIGN) {
 		//	TODO: handle syntax error
 		return nil
 	}
+	p.nextToken()
 
-	for !p.currTokenIsOfType(token.SEMICOLON) {
-		p.nextToken()
+	ls.Value = p.parseExpression(LOWEST)
+
+	if !p.eat(token.SEMICOLON) {
+		return nil
 	}
 	return ls
 }
 
+func (p *Parser) parseConstStatement() *ast.ConstStatement {
+	cs := &ast.ConstStatement{Token: p.currToken}
+
+	if !p.eat(token.IDENT) {
+		return nil
+	}
+	cs.Identifier = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+	cs.Annotation = p.parseTypeAnnotation()
+
+	if !p.eat(token.ASSIGN) {
+		return nil
+	}
+	p.nextToken()
+
+	cs.Value = p.parseExpression(LOWEST)
+
+	if !p.eat(token.SEMICOLON) {
+		return nil
+	}
+	return cs
+}
+
+// parseTypeAnnotation parses an optional ": type" following a declared name.
+// It returns nil, without consuming anything, when there is no colon.
+func (p *Parser) parseTypeAnnotation() *ast.TypeAnnotation {
+	if !p.peekTokenIsOfType(token.COLON) {
+		return nil
+	}
+	p.nextToken()
+
+	if !p.eat(token.IDENT) {
+		return nil
+	}
+	return &ast.TypeAnnotation{Token: p.currToken, Name: p.currToken.Literal}
+}
+
+func (p *Parser) parseImportStatement() *ast.ImportStatement {
+	is := &ast.ImportStatement{Token: p.currToken}
+
+	if !p.eat(token.STRING) {
+		return nil
+	}
+	is.Path = &ast.StringLiteral{Token: p.currToken, Value: p.currToken.Literal}
+
+	name := importName(is.Path.Value)
+	if name == "" {
+		p.errorf(is.Path.Token.Pos, "cannot derive a binding name from import path %q", is.Path.Value)
+		return nil
+	}
+	is.Name = &ast.Identifier{
+		Token: token.Token{Type: token.IDENT, Literal: name, Pos: is.Path.Token.Pos},
+		Value: name,
+	}
+
+	if !p.eat(token.SEMICOLON) {
+		return nil
+	}
+	return is
+}
+
+// importName derives the name an import is bound to from its path: the last
+// path element without a .mk extension. It returns "" if that isn't a valid
+// identifier.
+func importName(importPath string) string {
+	name := strings.TrimSuffix(path.Base(importPath), ".mk")
+	if name == "" {
+		return ""
+	}
+	for _, c := range name {
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_') {
+			return ""
+		}
+	}
+	return name
+}
+
+func (p *Parser) parseExportStatement() ast.Statement {
+	es := &ast.ExportStatement{Token: p.currToken}
+
+	if p.blockDepth != 0 {
+		p.errorf(es.Token.Pos, "export is only allowed at the top level of a module")
+	}
+	p.nextToken()
+
+	switch p.currToken.Type {
+	case token.LET:
+		if ls := p.parseLetStatement(); ls != nil {
+			es.Statement = ls
+		}
+	case token.CONST:
+		if cs := p.parseConstStatement(); cs != nil {
+			es.Statement = cs
+		}
+	default:
+		p.errorf(p.currToken.Pos, "expected let or const after export but got %s instead", p.currToken.Type)
+	}
+
+	if es.Statement == nil {
+		return nil
+	}
+	return es
+}
+
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	rs := &ast.ReturnStatement{Token: p.currToken}
 
-	for !p.currTokenIsOfType(token.SEMICOLON) {
+	if p.peekToken.Type == token.SEMICOLON {
 		p.nextToken()
+		return rs
+	}
+	p.nextToken()
+
+	rs.ReturnValue = p.parseExpression(LOWEST)
+
+	if !p.eat(token.SEMICOLON) {
+		return nil
 	}
 	return rs
 }
 
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	ws := &ast.WhileStatement{Token: p.currToken}
+
+	if !p.eat(token.LPAREN) {
+		return nil
+	}
+	p.nextToken()
+
+	ws.Condition = p.parseExpression(LOWEST)
+
+	if !p.eat(token.RPAREN) {
+		return nil
+	}
+
+	ws.Body = p.parseLoopBody()
+	if ws.Body == nil {
+		return nil
+	}
+	return ws
+}
+
+func (p *Parser) parseForInStatement() *ast.ForInStatement {
+	fs := &ast.ForInStatement{Token: p.currToken}
+
+	if !p.eat(token.LPAREN) {
+		return nil
+	}
+	if !p.eat(token.IDENT) {
+		return nil
+	}
+	fs.Variable = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+
+	if !p.eat(token.IN) {
+		return nil
+	}
+	p.nextToken()
+
+	fs.Iterable = p.parseExpression(LOWEST)
+
+	if !p.eat(token.RPAREN) {
+		return nil
+	}
+
+	fs.Body = p.parseLoopBody()
+
+	if fs.Body == nil {
+		return nil
+	}
+	return fs
+}
+
+// parseLoopBody parses the block following a loop header. A semicolon after
+// the closing brace is optional.
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	if !p.eat(token.LBRACE) {
+		return nil
+	}
+
+	p.loopDepth++
+	body := p.parseBlockStatement()
+	p.loopDepth--
+
+	if p.peekToken.Type == token.SEMICOLON {
+		p.nextToken()
+	}
+	return body
+}
+
+func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	bs := &ast.BlockStatement{Token: p.currToken, Statements: []ast.Statement{}}
+	p.nextToken()
+
+	p.blockDepth++
+	defer func() { p.blockDepth-- }()
+
+	for !p.currTokenIsOfType(token.RBRACE) {
+		if p.currTokenIsOfType(token.EOF) {
+			p.errorf(p.currToken.Pos, "expected %s to close the block opened at %s but got EOF instead", token.RBRACE, bs.Token.Pos)
+			return nil
+		}
+
+		statement := p.parseStatement()
+		if statement != nil {
+			bs.Statements = append(bs.Statements, statement)
+		}
+		p.nextToken()
+	}
+
+	return bs
+}
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	bs := &ast.BreakStatement{Token: p.currToken}
+
+	if p.loopDepth == 0 {
+		p.errorf(bs.Token.Pos, "break outside of a loop")
+	}
+	if !p.eat(token.SEMICOLON) {
+		return nil
+	}
+	return bs
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	cs := &ast.ContinueStatement{Token: p.currToken}
+
+	if p.loopDepth == 0 {
+		p.errorf(cs.Token.Pos, "continue outside of a loop")
+	}
+	if !p.eat(token.SEMICOLON) {
+		return nil
+	}
+	return cs
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	ts := &ast.ThrowStatement{Token: p.currToken}
+	p.nextToken()
+
+	ts.Value = p.parseExpression(LOWEST)
+
+	if !p.eat(token.SEMICOLON) {
+		return nil
+	}
+	return ts
+}
+
+func (p *Parser) parseTryStatement() *ast.TryStatement {
+	ts := &ast.TryStatement{Token: p.currToken}
+
+	if !p.eat(token.LBRACE) {
+		return nil
+	}
+	ts.Body = p.parseBlockStatement()
+	if ts.Body == nil {
+		return nil
+	}
+
+	if p.peekTokenIsOfType(token.CATCH) {
+		p.nextToken()
+		if !p.eat(token.LPAREN) {
+			return nil
+		}
+		if !p.eat(token.IDENT) {
+			return nil
+		}
+		ts.CatchParam = &ast.Identifier{Token: p.currToken, Value: p.currToken.Literal}
+		if !p.eat(token.RPAREN) {
+			return nil
+		}
+		if !p.eat(token.LBRACE) {
+			return nil
+		}
+
+		ts.Catch = p.parseBlockStatement()
+		if ts.Catch == nil {
+			return nil
+		}
+	}
+
+	if p.peekTokenIsOfType(token.FINALLY) {
+		p.nextToken()
+		if !p.eat(token.LBRACE) {
+			return nil
+		}
+		ts.Finally = p.parseBlockStatement()
+		if ts.Finally == nil {
+			return nil
+		}
+	}
+
+	if ts.Catch == nil && ts.Finally == nil {
+		p.errorf(ts.Token.Pos, "try without catch or finally")
+		return nil
+	}
+
+	if p.peekTokenIsOfType(token.SEMICOLON) {
+		p.nextToken()
+	}
+	return ts
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	es := &ast.ExpressionStatement{Token: p.currToken}
 
@@ -125,10 +485,21 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefixFn := p.prefixParseFns[p.currToken.Type]
 	if prefixFn == nil {
+		p.errorf(p.currToken.Pos, "no prefix parse function found for %s", p.currToken.Type)
 		return nil
 	}
 	leftExp := prefixFn()
 
+	for !p.peekTokenIsOfType(token.SEMICOLON) && precedence < p.peekPrecedence() {
+		infixFn := p.infixParseFns[p.peekToken.Type]
+		if infixFn == nil {
+			return leftExp
+		}
+		p.nextToken()
+
+		leftExp = infixFn(leftExp)
+	}
+
 	return leftExp
 }
 
@@ -139,15 +510,23 @@ func (p *Parser) parseIdentifier() ast.Expression {
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	intLiteral, err := strconv.Atoi(p.currToken.Literal)
 	if err != nil {
-		p.errors = append(p.errors, fmt.Sprintf("error converting intLiteral from string to int %v\n", err))
+		p.errorf(p.currToken.Pos, "integer literal %s is out of range", p.currToken.Literal)
 		return nil
 	}
 	return &ast.IntegerLiteral{Token: p.currToken, Value: intLiteral}
 }
 
+func (p *Parser) parseBooleanLiteral() ast.Expression {
+	return &ast.BooleanLiteral{Token: p.currToken, Value: p.currTokenIsOfType(token.TRUE)}
+}
+
+func (p *Parser) parseStringLiteral() ast.Expression {
+	return &ast.StringLiteral{Token: p.currToken, Value: p.currToken.Literal}
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	prefixExp := &ast.PrefixExpression{
-		Token:    token.NewToken(p.currToken.Type, p.currToken.Literal),
+		Token:    p.currToken,
 		Operator: p.currToken.Literal,
 	}
 
@@ -157,6 +536,49 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 	return prefixExp
 }
 
+// parseAssignExpression parses an assignment to target. Assignment is right
+// associative, so x = y = 5 assigns 5 to y and then to x.
+func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	infixExp := &ast.InfixExpression{
+		Token:    p.currToken,
+		Left:     left,
+		Operator: p.currToken.Literal,
+	}
+
+	precedence := p.currPrecedence()
+	p.nextToken()
+	infixExp.Right = p.parseExpression(precedence)
+
+	return infixExp
+}
+
+func (p *Parser) parseGroupedExpression() ast.Expression {
+	p.nextToken()
+
+	exp := p.parseExpression(LOWEST)
+
+	if !p.eat(token.RPAREN) {
+		return nil
+	}
+	return exp
+}
+
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	ae := &ast.AssignExpression{Token: p.currToken, Operator: p.currToken.Literal}
+
+	ident, ok := target.(*ast.Identifier)
+	if !ok {
+		p.errorf(ae.Token.Pos, "cannot assign to %s", target)
+		return nil
+	}
+	ae.Target = ident
+
+	p.nextToken()
+	ae.Value = p.parseExpression(ASSIGNMENT - 1)
+
+	return ae
+}
+
 func (p *Parser) eat(tt token.TokenType) bool {
 	if p.peekToken.Type == tt {
 		p.nextToken()
@@ -168,13 +590,37 @@ func (p *Parser) eat(tt token.TokenType) bool {
 }
 
 func (p *Parser) eatError(tt token.TokenType) {
-	p.errors = append(p.errors, fmt.Sprintf("expected next token of type %s but got %s instead\n", tt, p.peekToken.Type))
+	p.errorf(p.peekToken.Pos, "expected next token of type %s but got %s instead", tt, p.peekToken.Type)
+}
+
+// errorf records a syntax error at pos. Errors are formatted as
+// "line:col: message", so that callers can prefix them with a file name.
+func (p *Parser) errorf(pos token.Position, format string, args ...interface{}) {
+	p.errors = append(p.errors, fmt.Sprintf("%s: %s\n", pos, fmt.Sprintf(format, args...)))
 }
 
 func (p *Parser) currTokenIsOfType(tt token.TokenType) bool {
 	return p.currToken.Type == tt
 }
 
+func (p *Parser) peekTokenIsOfType(tt token.TokenType) bool {
+	return p.peekToken.Type == tt
+}
+
+func (p *Parser) currPrecedence() int {
+	if precedence, ok := precedences[p.currToken.Type]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
+func (p *Parser) peekPrecedence() int {
+	if precedence, ok := precedences[p.peekToken.Type]; ok {
+		return precedence
+	}
+	return LOWEST
+}
+
 func (p *Parser) registerPrefixParseFn(tt token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tt] = fn
 }
@@ -72,6 +72,32 @@ return 17290022;
 	}
 }
 
+func TestParseStatementValues(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected string
+	}{
+		{input: "let x = 5;", expected: "let x = 5;"},
+		{input: "let y = -foo;", expected: "let y = -foo;"},
+		{input: "return !5;", expected: "return !5;"},
+		{input: "return;", expected: "return;"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements doesn't contain 1 statement. Got = %d\n", len(program.Statements))
+		}
+
+		if program.String() != tc.expected {
+			t.Errorf("program.String() wrong. Expected %s but got %s\n", tc.expected, program.String())
+		}
+	}
+}
+
 func TestString(t *testing.T) {
 	program := &ast.Program{
 		Statements: []ast.Statement{
@@ -162,6 +188,40 @@ func TestParseIntegerLiteral(t *testing.T) {
 	}
 }
 
+func TestParseBooleanLiteral(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected bool
+	}{
+		{"true;", true},
+		{"false;", false},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements doesn't contain 1 statement. Got = %d\n", len(program.Statements))
+		}
+
+		expStmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("stmt %v is not an ast.ExpressionStatement\n", program.Statements[0])
+		}
+
+		boolLiteral, ok := expStmt.Expression.(*ast.BooleanLiteral)
+		if !ok {
+			t.Fatalf("expStmt.Expression is not of type BooleanLiteral. Got %T\n", expStmt.Expression)
+		}
+
+		if boolLiteral.Value != tc.expected {
+			t.Errorf("boolLiteral is not of value %t. Got = %t\n", tc.expected, boolLiteral.Value)
+		}
+	}
+}
+
 func TestParsePrefixExpressions(t *testing.T) {
 	tt := []struct {
 		input        string
@@ -205,6 +265,461 @@ func TestParsePrefixExpressions(t *testing.T) {
 	}
 }
 
+func TestParseInfixExpressions(t *testing.T) {
+	tt := []struct {
+		input    string
+		left     int
+		operator string
+		right    int
+	}{
+		{input: "5 + 5;", left: 5, operator: "+", right: 5},
+		{input: "5 - 5;", left: 5, operator: "-", right: 5},
+		{input: "5 * 5;", left: 5, operator: "*", right: 5},
+		{input: "5 / 5;", left: 5, operator: "/", right: 5},
+		{input: "5 > 5;", left: 5, operator: ">", right: 5},
+		{input: "5 < 5;", left: 5, operator: "<", right: 5},
+		{input: "5 == 5;", left: 5, operator: "==", right: 5},
+		{input: "5 != 5;", left: 5, operator: "!=", right: 5},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements doesn't contain 1 statement. Got = %d\n", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("stmt %v is not an ast.ExpressionStatement\n", program.Statements[0])
+		}
+
+		exp, ok := stmt.Expression.(*ast.InfixExpression)
+		if !ok {
+			t.Fatalf("stmt.Expression is not of type ast.InfixExpression. Got %T\n", stmt.Expression)
+		}
+
+		if !testIntegerLiteral(t, exp.Left, tc.left) {
+			return
+		}
+
+		if exp.Operator != tc.operator {
+			t.Fatalf("Expected exp.Operator to be %s. Got %s\n", tc.operator, exp.Operator)
+		}
+
+		if !testIntegerLiteral(t, exp.Right, tc.right) {
+			return
+		}
+	}
+}
+
+func TestOperatorPrecedenceParsing(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected string
+	}{
+		{"-a * b;", "(-a * b);"},
+		{"!-a;", "!-a;"},
+		{"a + b + c;", "((a + b) + c);"},
+		{"a + b - c;", "((a + b) - c);"},
+		{"a * b * c;", "((a * b) * c);"},
+		{"a * b / c;", "((a * b) / c);"},
+		{"a + b / c;", "(a + (b / c));"},
+		{"a + b * c + d / e - f;", "(((a + (b * c)) + (d / e)) - f);"},
+		{"5 > 4 == 3 < 4;", "((5 > 4) == (3 < 4));"},
+		{"5 < 4 != 3 > 4;", "((5 < 4) != (3 > 4));"},
+		{"3 + 4 * 5 == 3 * 1 + 4 * 5;", "((3 + (4 * 5)) == ((3 * 1) + (4 * 5)));"},
+		{"(5 + 5) * 2;", "((5 + 5) * 2);"},
+		{"-(5 + 5);", "-(5 + 5);"},
+		{"x = y += 1 + 2;", "x = y += (1 + 2);"},
+		{"let x = 1 + 2 * 3;", "let x = (1 + (2 * 3));"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if program.String() != tc.expected {
+			t.Errorf("program.String() wrong for %q. Expected %s but got %s\n", tc.input, tc.expected, program.String())
+		}
+	}
+}
+
+func TestParseLoops(t *testing.T) {
+	input := `
+while (x) {
+	for (item in items) {
+		continue;
+	}
+	break;
+};
+`
+
+	parser := New(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements doesn't contain 1 statement. Got = %d\n", len(program.Statements))
+	}
+
+	while, ok := program.Statements[0].(*ast.WhileStatement)
+	if !ok {
+		t.Fatalf("stmt %v is not an ast.WhileStatement\n", program.Statements[0])
+	}
+
+	if while.Condition.String() != "x" {
+		t.Fatalf("while.Condition is not x. Got = %s\n", while.Condition.String())
+	}
+
+	if len(while.Body.Statements) != 2 {
+		t.Fatalf("while.Body doesn't contain 2 statements. Got = %d\n", len(while.Body.Statements))
+	}
+
+	forIn, ok := while.Body.Statements[0].(*ast.ForInStatement)
+	if !ok {
+		t.Fatalf("stmt %v is not an ast.ForInStatement\n", while.Body.Statements[0])
+	}
+
+	if forIn.Variable.Value != "item" || forIn.Iterable.String() != "items" {
+		t.Fatalf("forIn header wrong. Got = %s\n", forIn.String())
+	}
+
+	if _, ok := forIn.Body.Statements[0].(*ast.ContinueStatement); !ok {
+		t.Fatalf("stmt %v is not an ast.ContinueStatement\n", forIn.Body.Statements[0])
+	}
+
+	if _, ok := while.Body.Statements[1].(*ast.BreakStatement); !ok {
+		t.Fatalf("stmt %v is not an ast.BreakStatement\n", while.Body.Statements[1])
+	}
+
+	expected := "while (x) {for (item in items) {continue;}break;}"
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. Expected %s but got %s\n", expected, program.String())
+	}
+}
+
+func TestParseLoopErrors(t *testing.T) {
+	tt := []struct {
+		input string
+		err   string
+	}{
+		{input: "break;", err: "1:1: break outside of a loop\n"},
+		{input: "while (x) { }\ncontinue;", err: "2:1: continue outside of a loop\n"},
+		{input: "while (x) { x;", err: "1:15: expected RBRACE to close the block opened at 1:11 but got EOF instead\n"},
+		{input: "for (x items) { }", err: "1:8: expected next token of type IN but got IDENT instead\n"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		parser.ParseProgram()
+
+		errs := parser.Errors()
+		if len(errs) == 0 || errs[0] != tc.err {
+			t.Errorf("Expected first error for %q to be %q. Got = %q\n", tc.input, tc.err, errs)
+		}
+	}
+}
+
+func TestParseAssignExpressions(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected string
+	}{
+		{input: "let x = 1; x = 5;", expected: "x = 5;"},
+		{input: "let x = 1; x += -y;", expected: "x += -y;"},
+		{input: "let x = 1; x -= 2;", expected: "x -= 2;"},
+		{input: "let x = 1; x *= 2;", expected: "x *= 2;"},
+		{input: "let x = 1; x /= 2;", expected: "x /= 2;"},
+		{input: "let x = 1; let y = 2; x = y = 3;", expected: "x = y = 3;"},
+		{input: "let x = 1; while (x) { x = 0; }", expected: "while (x) {x = 0;}"},
+		{input: "for (i in xs) { i += 1; }", expected: "for (i in xs) {i += 1;}"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		last := program.Statements[len(program.Statements)-1]
+		if last.String() != tc.expected {
+			t.Errorf("stmt.String() wrong. Expected %s but got %s\n", tc.expected, last.String())
+		}
+	}
+}
+
+func TestParseAssignExpressionIsRightAssociative(t *testing.T) {
+	parser := New(lexer.New("let x = 1; let y = 2; x = y = 3;"))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	stmt := program.Statements[2].(*ast.ExpressionStatement)
+	outer, ok := stmt.Expression.(*ast.AssignExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not an ast.AssignExpression. Got %T\n", stmt.Expression)
+	}
+
+	if outer.Target.Value != "x" {
+		t.Fatalf("outer assignment target is not x. Got = %s\n", outer.Target.Value)
+	}
+
+	inner, ok := outer.Value.(*ast.AssignExpression)
+	if !ok || inner.Target.Value != "y" {
+		t.Fatalf("outer.Value is not an assignment to y. Got = %s\n", outer.Value)
+	}
+}
+
+func TestParseAssignExpressionErrors(t *testing.T) {
+	tt := []struct {
+		input string
+		err   string
+	}{
+		{input: "5 = 3;", err: "1:3: cannot assign to 5\n"},
+		{input: "let x = 1; -x += 3;", err: "1:15: cannot assign to -x\n"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		parser.ParseProgram()
+
+		errs := parser.Errors()
+		if len(errs) == 0 || errs[0] != tc.err {
+			t.Errorf("Expected first error for %q to be %q. Got = %q\n", tc.input, tc.err, errs)
+		}
+	}
+}
+
+func TestParseConstStatements(t *testing.T) {
+	input := `
+const limit = 10;
+let x = -limit;
+while (x) { const limit = 5; }
+`
+
+	parser := New(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	cs, ok := program.Statements[0].(*ast.ConstStatement)
+	if !ok {
+		t.Fatalf("stmt %v is not an ast.ConstStatement\n", program.Statements[0])
+	}
+
+	if cs.Identifier.Value != "limit" || cs.Value.String() != "10" {
+		t.Fatalf("const statement wrong. Got = %s\n", cs.String())
+	}
+
+	if cs.Identifier.Token.Pos.String() != "2:7" {
+		t.Fatalf("const identifier has the wrong position. Got = %s\n", cs.Identifier.Token.Pos)
+	}
+}
+
+func TestParseTypeAnnotations(t *testing.T) {
+	tt := []struct {
+		input      string
+		annotation string
+		expected   string
+	}{
+		{input: "let x: int = 5;", annotation: "int", expected: "let x: int = 5;"},
+		{input: "const ok: bool = !0;", annotation: "bool", expected: "const ok: bool = !0;"},
+		{input: "let y = 5;", annotation: "", expected: "let y = 5;"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		var annotation *ast.TypeAnnotation
+		switch stmt := program.Statements[0].(type) {
+		case *ast.LetStatement:
+			annotation = stmt.Annotation
+		case *ast.ConstStatement:
+			annotation = stmt.Annotation
+		}
+
+		if tc.annotation == "" && annotation != nil {
+			t.Errorf("Expected no annotation for %q. Got = %s\n", tc.input, annotation)
+		}
+		if tc.annotation != "" && (annotation == nil || annotation.Name != tc.annotation) {
+			t.Errorf("Expected annotation %s for %q. Got = %v\n", tc.annotation, tc.input, annotation)
+		}
+
+		if program.String() != tc.expected {
+			t.Errorf("program.String() wrong. Expected %s but got %s\n", tc.expected, program.String())
+		}
+	}
+}
+
+func TestParseImportAndExportStatements(t *testing.T) {
+	input := `
+import "lib/strings";
+import "./util.mk";
+export let greeting = "hello";
+export const limit: int = 10;
+`
+
+	parser := New(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("program.Statements doesn't contain 4 statements. Got = %d\n", len(program.Statements))
+	}
+
+	tests := []struct {
+		path string
+		name string
+	}{
+		{"lib/strings", "strings"},
+		{"./util.mk", "util"},
+	}
+
+	for i, test := range tests {
+		is, ok := program.Statements[i].(*ast.ImportStatement)
+		if !ok {
+			t.Fatalf("stmt %v is not an ast.ImportStatement\n", program.Statements[i])
+		}
+
+		if is.Path.Value != test.path || is.Name.Value != test.name {
+			t.Errorf("import wrong. Expected path %s bound to %s, got %s bound to %s\n", test.path, test.name, is.Path.Value, is.Name.Value)
+		}
+	}
+
+	es, ok := program.Statements[2].(*ast.ExportStatement)
+	if !ok {
+		t.Fatalf("stmt %v is not an ast.ExportStatement\n", program.Statements[2])
+	}
+
+	if _, ok := es.Statement.(*ast.LetStatement); !ok {
+		t.Fatalf("es.Statement is not an ast.LetStatement. Got %T\n", es.Statement)
+	}
+
+	expected := `import "lib/strings";import "./util.mk";export let greeting = "hello";export const limit: int = 10;`
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. Expected %s but got %s\n", expected, program.String())
+	}
+}
+
+func TestParseStringLiteralSpellingKeyword(t *testing.T) {
+	for _, keyword := range []string{"let", "const", "import", "export", "return", "while", "for", "break", "continue", "throw", "try"} {
+		input := `"` + keyword + `";`
+
+		parser := New(lexer.New(input))
+		program := parser.ParseProgram()
+		checkParserErrors(t, parser)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements for %s doesn't contain 1 statement. Got = %d\n", input, len(program.Statements))
+		}
+
+		es, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("stmt %v is not an ast.ExpressionStatement\n", program.Statements[0])
+		}
+
+		sl, ok := es.Expression.(*ast.StringLiteral)
+		if !ok || sl.Value != keyword {
+			t.Errorf("expression for %s is not the string literal %q. Got = %#v\n", input, keyword, es.Expression)
+		}
+	}
+}
+
+func TestParseImportAndExportErrors(t *testing.T) {
+	tt := []struct {
+		input string
+		err   string
+	}{
+		{input: `import "lib/my-strings";`, err: "1:8: cannot derive a binding name from import path \"lib/my-strings\"\n"},
+		{input: `import strings;`, err: "1:8: expected next token of type STRING but got IDENT instead\n"},
+		{input: `while (x) { export let y = 1; }`, err: "1:13: export is only allowed at the top level of a module\n"},
+		{input: `export 5;`, err: "1:8: expected let or const after export but got INT instead\n"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		parser.ParseProgram()
+
+		errs := parser.Errors()
+		if len(errs) == 0 || errs[0] != tc.err {
+			t.Errorf("Expected first error for %q to be %q. Got = %q\n", tc.input, tc.err, errs)
+		}
+	}
+}
+
+func TestParseTryAndThrowStatements(t *testing.T) {
+	input := `
+try {
+	throw "boom";
+} catch (e) {
+	e;
+} finally {
+	x;
+};
+try { } finally { }
+`
+
+	parser := New(lexer.New(input))
+	program := parser.ParseProgram()
+	checkParserErrors(t, parser)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements doesn't contain 2 statements. Got = %d\n", len(program.Statements))
+	}
+
+	ts, ok := program.Statements[0].(*ast.TryStatement)
+	if !ok {
+		t.Fatalf("stmt %v is not an ast.TryStatement\n", program.Statements[0])
+	}
+
+	if _, ok := ts.Body.Statements[0].(*ast.ThrowStatement); !ok {
+		t.Fatalf("stmt %v is not an ast.ThrowStatement\n", ts.Body.Statements[0])
+	}
+
+	if ts.CatchParam.Value != "e" || ts.Catch == nil || ts.Finally == nil {
+		t.Fatalf("try clauses wrong. Got = %s\n", ts.String())
+	}
+
+	ts, ok = program.Statements[1].(*ast.TryStatement)
+	if !ok {
+		t.Fatalf("stmt %v is not an ast.TryStatement\n", program.Statements[1])
+	}
+
+	if ts.CatchParam != nil || ts.Catch != nil || ts.Finally == nil {
+		t.Fatalf("try clauses wrong. Got = %s\n", ts.String())
+	}
+
+	expected := `try {throw "boom";} catch (e) {e;} finally {x;}try {} finally {}`
+	if program.String() != expected {
+		t.Errorf("program.String() wrong. Expected %s but got %s\n", expected, program.String())
+	}
+}
+
+func TestParseTryErrors(t *testing.T) {
+	tt := []struct {
+		input string
+		err   string
+	}{
+		{input: "try { }", err: "1:1: try without catch or finally\n"},
+		{input: "try { } catch { }", err: "1:15: expected next token of type LPAREN but got LBRACE instead\n"},
+		{input: "throw;", err: "1:6: no prefix parse function found for SEMICOLON\n"},
+		{input: "let x =\n  99999999999999999999;", err: "2:3: integer literal 99999999999999999999 is out of range\n"},
+	}
+
+	for _, tc := range tt {
+		parser := New(lexer.New(tc.input))
+		parser.ParseProgram()
+
+		errs := parser.Errors()
+		if len(errs) == 0 || errs[0] != tc.err {
+			t.Errorf("Expected first error for %q to be %q. Got = %q\n", tc.input, tc.err, errs)
+		}
+	}
+}
+
 // helper functions
 
 func testIntegerLiteral(t *testing.T, il ast.Expression, value int) bool {
@@ -238,8 +753,8 @@ func testLetStatement(t *testing.T, stmt ast.Statement, expectedIdentifierValue
 		return false
 	}
 
-	if letStmt.Identifier.TokenLiteral() != "ident" {
-		t.Errorf("letStmt.Identifier.TokenLiteral() is not let. Got = %s\n", letStmt.Identifier.TokenLiteral())
+	if letStmt.Identifier.TokenLiteral() != expectedIdentifierValue {
+		t.Errorf("letStmt.Identifier.TokenLiteral() is not %s. Got = %s\n", expectedIdentifierValue, letStmt.Identifier.TokenLiteral())
 		return false
 	}
 
@@ -0,0 +1,222 @@
+// Package printer implements printing of AST nodes in the canonical Monkey
+// source format used by monkey fmt.
+package printer
+
+import (
+	"../ast"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const indentation = "\t"
+
+type printer struct {
+	sb     strings.Builder
+	indent int
+}
+
+// Fprint writes the canonical source form of node to w. Every statement is
+// printed on a line of its own, indented with one tab per enclosing block.
+// Statements are terminated by a semicolon, except loops and try statements,
+// which end with a closing brace on its own line.
+func Fprint(w io.Writer, node ast.Node) error {
+	p := &printer{}
+
+	switch n := node.(type) {
+	case *ast.Program:
+		p.program(n)
+	case ast.Statement:
+		p.statement(n)
+	case ast.Expression:
+		p.expression(n)
+	default:
+		return fmt.Errorf("printer.Fprint: unexpected node type %T", n)
+	}
+
+	_, err := io.WriteString(w, p.sb.String())
+	return err
+}
+
+func (p *printer) program(program *ast.Program) {
+	for _, stmt := range program.Statements {
+		p.statement(stmt)
+	}
+}
+
+func (p *printer) statement(stmt ast.Statement) {
+	p.sb.WriteString(strings.Repeat(indentation, p.indent))
+
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		p.sb.WriteString("let ")
+		p.expression(s.Identifier)
+		p.annotation(s.Annotation)
+		p.sb.WriteString(" = ")
+		p.expression(s.Value)
+	case *ast.ConstStatement:
+		p.sb.WriteString("const ")
+		p.expression(s.Identifier)
+		p.annotation(s.Annotation)
+		p.sb.WriteString(" = ")
+		p.expression(s.Value)
+	case *ast.ReturnStatement:
+		p.sb.WriteString("return")
+		if s.ReturnValue != nil {
+			p.sb.WriteString(" ")
+			p.expression(s.ReturnValue)
+		}
+	case *ast.ExpressionStatement:
+		p.expression(s.Expression)
+	case *ast.ImportStatement:
+		p.sb.WriteString("import ")
+		p.expression(s.Path)
+	case *ast.ExportStatement:
+		p.sb.WriteString("export ")
+		indent := p.indent
+		p.indent = 0
+		p.statement(s.Statement)
+		p.indent = indent
+		return
+	case *ast.BreakStatement:
+		p.sb.WriteString("break")
+	case *ast.ContinueStatement:
+		p.sb.WriteString("continue")
+	case *ast.WhileStatement:
+		p.sb.WriteString("while (")
+		p.expression(s.Condition)
+		p.sb.WriteString(") ")
+		p.block(s.Body)
+		p.sb.WriteString("\n")
+		return
+	case *ast.ForInStatement:
+		p.sb.WriteString("for (")
+		p.expression(s.Variable)
+		p.sb.WriteString(" in ")
+		p.expression(s.Iterable)
+		p.sb.WriteString(") ")
+		p.block(s.Body)
+		p.sb.WriteString("\n")
+		return
+	case *ast.ThrowStatement:
+		p.sb.WriteString("throw ")
+		p.expression(s.Value)
+	case *ast.TryStatement:
+		p.sb.WriteString("try ")
+		p.block(s.Body)
+		if s.Catch != nil {
+			p.sb.WriteString(" catch (")
+			p.expression(s.CatchParam)
+			p.sb.WriteString(") ")
+			p.block(s.Catch)
+		}
+		if s.Finally != nil {
+			p.sb.WriteString(" finally ")
+			p.block(s.Finally)
+		}
+		p.sb.WriteString("\n")
+		return
+	case *ast.BlockStatement:
+		p.block(s)
+		p.sb.WriteString("\n")
+		return
+	default:
+		panic(fmt.Sprintf("printer: unexpected statement type %T", s))
+	}
+
+	p.sb.WriteString(";\n")
+}
+
+func (p *printer) annotation(annotation *ast.TypeAnnotation) {
+	if annotation != nil {
+		p.sb.WriteString(": " + annotation.Name)
+	}
+}
+
+// block prints a brace-delimited block starting at the current position and
+// leaves the output right after the closing brace.
+func (p *printer) block(block *ast.BlockStatement) {
+	p.sb.WriteString("{\n")
+
+	p.indent++
+	for _, stmt := range block.Statements {
+		p.statement(stmt)
+	}
+	p.indent--
+
+	p.sb.WriteString(strings.Repeat(indentation, p.indent))
+	p.sb.WriteString("}")
+}
+
+// Operator precedences, from loosest to tightest binding. They mirror the
+// parser's.
+const (
+	lowest = iota
+	assignment
+	equals
+	lessGreater
+	sum
+	product
+	prefix
+)
+
+var precedences = map[string]int{
+	"==": equals,
+	"!=": equals,
+	"<":  lessGreater,
+	">":  lessGreater,
+	"+":  sum,
+	"-":  sum,
+	"*":  product,
+	"/":  product,
+}
+
+func (p *printer) expression(exp ast.Expression) {
+	p.operand(exp, lowest)
+}
+
+// operand prints exp where an expression binding at least as tightly as
+// precedence is expected, wrapping it in parentheses if it binds more loosely.
+func (p *printer) operand(exp ast.Expression, precedence int) {
+	switch e := exp.(type) {
+	case *ast.Identifier:
+		p.sb.WriteString(e.Value)
+	case *ast.IntegerLiteral:
+		p.sb.WriteString(e.Token.Literal)
+	case *ast.BooleanLiteral:
+		p.sb.WriteString(e.Token.Literal)
+	case *ast.StringLiteral:
+		p.sb.WriteString(`"` + e.Value + `"`)
+	case *ast.PrefixExpression:
+		p.sb.WriteString(e.Operator)
+		p.operand(e.Right, prefix)
+	case *ast.InfixExpression:
+		own := precedences[e.Operator]
+		p.open(own < precedence)
+		// Infix operators are left-associative.
+		p.operand(e.Left, own)
+		p.sb.WriteString(" " + e.Operator + " ")
+		p.operand(e.Right, own+1)
+		p.close(own < precedence)
+	case *ast.AssignExpression:
+		p.open(assignment < precedence)
+		p.expression(e.Target)
+		p.sb.WriteString(" " + e.Operator + " ")
+		p.operand(e.Value, assignment)
+		p.close(assignment < precedence)
+	default:
+		panic(fmt.Sprintf("printer: unexpected expression type %T", e))
+	}
+}
+
+func (p *printer) open(parenthesize bool) {
+	if parenthesize {
+		p.sb.WriteString("(")
+	}
+}
+
+func (p *printer) close(parenthesize bool) {
+	if parenthesize {
+		p.sb.WriteString(")")
+	}
+}
@@ -0,0 +1,67 @@
+package printer
+
+import (
+	"../lexer"
+	"../parser"
+	"bytes"
+	"testing"
+)
+
+func TestFprint(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 5;", "let x = 5;\n"},
+		{"let   x=-5 ;", "let x = -5;\n"},
+		{"return x;return;", "return x;\nreturn;\n"},
+		{"!-foo;\n\n\n5;", "!-foo;\n5;\n"},
+		{"", ""},
+		{"let x=1;x+=2;x=x=-1;", "let x = 1;\nx += 2;\nx = x = -1;\n"},
+		{"const   x=5;", "const x = 5;\n"},
+		{"let x=1+2*3-(4-5)/-6;", "let x = 1 + 2 * 3 - (4 - 5) / -6;\n"},
+		{"((a+b))*(c==d)!=(e<f);", "(a + b) * (c == d) != e < f;\n"},
+		{"-(a+b)+(x=1)+((y+=2)*3);", "-(a + b) + (x = 1) + (y += 2) * 3;\n"},
+		{"const x=5;let x=y;x=1;", "const x = 5;\nlet x = y;\nx = 1;\n"},
+		{"let x :int=5; const y:bool = !x;", "let x: int = 5;\nconst y: bool = !x;\n"},
+		{"import   \"lib/strings\" ;export let s=\"hi\";", "import \"lib/strings\";\nexport let s = \"hi\";\n"},
+		{"while(x){}", "while (x) {\n}\n"},
+		{"while(true){break;};!false;", "while (true) {\n\tbreak;\n}\n!false;\n"},
+		{
+			"while (x) { for (i in xs) { let y = i; continue; } break; };",
+			"while (x) {\n\tfor (i in xs) {\n\t\tlet y = i;\n\t\tcontinue;\n\t}\n\tbreak;\n}\n",
+		},
+		{
+			"try{throw   \"boom\";}catch(e){!e;}finally{};try{}finally{}",
+			"try {\n\tthrow \"boom\";\n} catch (e) {\n\t!e;\n} finally {\n}\ntry {\n} finally {\n}\n",
+		},
+	}
+
+	for _, tc := range tt {
+		formatted := format(t, tc.input)
+
+		if formatted != tc.expected {
+			t.Errorf("Fprint(%q) wrong. Expected = %q, got = %q\n", tc.input, tc.expected, formatted)
+		}
+
+		if again := format(t, formatted); again != formatted {
+			t.Errorf("Fprint is not idempotent for %q. First = %q, second = %q\n", tc.input, formatted, again)
+		}
+	}
+}
+
+func format(t *testing.T, input string) string {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if errs := p.Errors(); len(errs) != 0 {
+		t.Fatalf("parser errors for %q: %v\n", input, errs)
+	}
+
+	var buf bytes.Buffer
+	if err := Fprint(&buf, program); err != nil {
+		t.Fatalf("Fprint(%q) returned error %v\n", input, err)
+	}
+
+	return buf.String()
+}
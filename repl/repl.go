@@ -17,7 +17,7 @@ func Start(in io.Reader, out io.Writer) {
 		_, _ = fmt.Fprintf(out, PROMPT)
 		instruction, err := reader.ReadString('\n')
 
-		if err != nil {
+		if err != nil && err != io.EOF {
 			panic(fmt.Errorf("repl.Start() threw %v\n", err))
 		}
 		l := lexer.New(instruction)
@@ -25,5 +25,11 @@ func Start(in io.Reader, out io.Writer) {
 		for nt := l.NextToken(); nt.Type != token.EOF; nt = l.NextToken() {
 			_, _ = fmt.Fprintf(out, "%+v\n", nt)
 		}
+
+		// The last line of piped input may lack a newline; it has been
+		// handled above along with any other data read before EOF.
+		if err == io.EOF {
+			return
+		}
 	}
 }
@@ -0,0 +1,281 @@
+// Package resolver binds the identifiers in a program to the declarations
+// they refer to and reports names that are undefined, used before their
+// definition, redeclared in the same block, assigned to although constant,
+// or declared and never used.
+package resolver
+
+import (
+	"../ast"
+	"../token"
+	"fmt"
+	"sort"
+)
+
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+)
+
+// Diagnostic is a problem found at a position in the source.
+type Diagnostic struct {
+	Pos      token.Position
+	Severity Severity
+	Msg      string
+}
+
+func (d Diagnostic) String() string {
+	if d.Severity == Warning {
+		return fmt.Sprintf("%s: warning: %s", d.Pos, d.Msg)
+	}
+	return fmt.Sprintf("%s: %s", d.Pos, d.Msg)
+}
+
+// Binding is a name declared by a let, const or import statement, a for-in
+// loop or a catch clause.
+type Binding struct {
+	Name *ast.Identifier
+	Kind string // "let", "const", "import", "loop variable" or "catch parameter"
+	// Exported bindings may be read by importing modules, so they are
+	// never reported as unused.
+	Exported bool
+	// Reads are the identifiers that read the binding. Assignments to it
+	// are not reads.
+	Reads []*ast.Identifier
+}
+
+// Result is the outcome of resolving a program.
+type Result struct {
+	Bindings []*Binding
+	// Uses maps every identifier that refers to a binding, including
+	// assignment targets, to that binding.
+	Uses        map[*ast.Identifier]*Binding
+	Diagnostics []Diagnostic
+}
+
+// HasErrors reports whether any diagnostic is an error rather than a
+// warning.
+func (r *Result) HasErrors() bool {
+	for _, d := range r.Diagnostics {
+		if d.Severity == Error {
+			return true
+		}
+	}
+	return false
+}
+
+type scope struct {
+	outer    *scope
+	bindings map[string]*Binding
+	// pending holds the position of the first declaration of each name
+	// declared directly in this scope, so that a use preceding it can be
+	// told apart from a use of an undefined name.
+	pending map[string]token.Position
+}
+
+type resolver struct {
+	scope  *scope
+	result *Result
+}
+
+// Resolve walks program and returns its bindings, the binding of each
+// identifier, and any diagnostics sorted by position.
+func Resolve(program *ast.Program) *Result {
+	r := &resolver{result: &Result{Uses: make(map[*ast.Identifier]*Binding)}}
+
+	r.block(program.Statements, nil)
+
+	sort.SliceStable(r.result.Diagnostics, func(i, j int) bool {
+		a, b := r.result.Diagnostics[i].Pos, r.result.Diagnostics[j].Pos
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	return r.result
+}
+
+// block resolves stmts in a new scope. The given bindings are declared up
+// front in a scope of their own enclosing it, so that stmts may shadow them.
+func (r *resolver) block(stmts []ast.Statement, predeclared []*Binding) {
+	if len(predeclared) != 0 {
+		r.openScope()
+		for _, b := range predeclared {
+			r.declare(b)
+		}
+		defer r.closeScope()
+	}
+
+	r.openScope()
+
+	for _, stmt := range stmts {
+		if ident := declaredIdentifier(stmt); ident != nil {
+			if _, ok := r.scope.pending[ident.Value]; !ok {
+				r.scope.pending[ident.Value] = ident.Token.Pos
+			}
+		}
+	}
+	for _, stmt := range stmts {
+		r.statement(stmt)
+	}
+
+	r.closeScope()
+}
+
+func (r *resolver) openScope() {
+	r.scope = &scope{
+		outer:    r.scope,
+		bindings: make(map[string]*Binding),
+		pending:  make(map[string]token.Position),
+	}
+}
+
+func (r *resolver) closeScope() {
+	r.reportUnused()
+	r.scope = r.scope.outer
+}
+
+func (r *resolver) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		r.expression(s.Value)
+		r.declare(&Binding{Name: s.Identifier, Kind: "let"})
+	case *ast.ConstStatement:
+		r.expression(s.Value)
+		r.declare(&Binding{Name: s.Identifier, Kind: "const"})
+	case *ast.ImportStatement:
+		r.declare(&Binding{Name: s.Name, Kind: "import"})
+	case *ast.ExportStatement:
+		r.statement(s.Statement)
+		if ident := declaredIdentifier(s.Statement); ident != nil {
+			if b, ok := r.scope.bindings[ident.Value]; ok {
+				b.Exported = true
+			}
+		}
+	case *ast.BlockStatement:
+		r.block(s.Statements, nil)
+	case *ast.WhileStatement:
+		r.expression(s.Condition)
+		if s.Body != nil {
+			r.block(s.Body.Statements, nil)
+		}
+	case *ast.ForInStatement:
+		r.expression(s.Iterable)
+		if s.Body != nil {
+			r.block(s.Body.Statements, []*Binding{{Name: s.Variable, Kind: "loop variable"}})
+		}
+	case *ast.ThrowStatement:
+		r.expression(s.Value)
+	case *ast.TryStatement:
+		if s.Body != nil {
+			r.block(s.Body.Statements, nil)
+		}
+		if s.Catch != nil {
+			r.block(s.Catch.Statements, []*Binding{{Name: s.CatchParam, Kind: "catch parameter"}})
+		}
+		if s.Finally != nil {
+			r.block(s.Finally.Statements, nil)
+		}
+	default:
+		r.expression(s)
+	}
+}
+
+// expression resolves every identifier in node as a read, except for the
+// targets of assignments.
+func (r *resolver) expression(node ast.Node) {
+	if node == nil {
+		return
+	}
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.Identifier:
+			if b := r.use(n); b != nil {
+				b.Reads = append(b.Reads, n)
+			}
+		case *ast.AssignExpression:
+			if n.Target != nil {
+				if b := r.use(n.Target); b != nil && b.Kind == "const" {
+					r.report(n.Target.Token.Pos, Error, "cannot assign to constant %s (declared at %s)", n.Target.Value, b.Name.Token.Pos)
+				}
+			}
+			if n.Value != nil {
+				r.expression(n.Value)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// declare binds b in the current scope, unless the scope already binds its
+// name.
+func (r *resolver) declare(b *Binding) {
+	if b.Name == nil {
+		return
+	}
+	if prev, ok := r.scope.bindings[b.Name.Value]; ok {
+		r.report(b.Name.Token.Pos, Error, "%s redeclared in this block (previous %s declaration at %s)", b.Name.Value, prev.Kind, prev.Name.Token.Pos)
+		return
+	}
+	r.scope.bindings[b.Name.Value] = b
+	r.result.Bindings = append(r.result.Bindings, b)
+}
+
+// use binds ident to the innermost binding of its name, or reports why it
+// can't.
+func (r *resolver) use(ident *ast.Identifier) *Binding {
+	for s := r.scope; s != nil; s = s.outer {
+		if b, ok := s.bindings[ident.Value]; ok {
+			r.result.Uses[ident] = b
+			return b
+		}
+	}
+
+	for s := r.scope; s != nil; s = s.outer {
+		if pos, ok := s.pending[ident.Value]; ok {
+			r.report(ident.Token.Pos, Error, "%s used before its definition at %s", ident.Value, pos)
+			return nil
+		}
+	}
+
+	r.report(ident.Token.Pos, Error, "undefined: %s", ident.Value)
+	return nil
+}
+
+// reportUnused warns about the unread bindings of the current scope. A catch
+// clause has to name the thrown value even when it only needs to know that
+// something was thrown, so catch parameters are exempt. Imports are exempt
+// too: a module is imported for its exports, and nothing can read those
+// through the import's name until the language has member access.
+func (r *resolver) reportUnused() {
+	for _, b := range r.scope.bindings {
+		if len(b.Reads) == 0 && !b.Exported && b.Kind != "catch parameter" && b.Kind != "import" {
+			r.report(b.Name.Token.Pos, Warning, "%s declared and not used", b.Name.Value)
+		}
+	}
+}
+
+func (r *resolver) report(pos token.Position, severity Severity, format string, args ...interface{}) {
+	r.result.Diagnostics = append(r.result.Diagnostics, Diagnostic{
+		Pos:      pos,
+		Severity: severity,
+		Msg:      fmt.Sprintf(format, args...),
+	})
+}
+
+// declaredIdentifier returns the name a statement declares in its own
+// scope, if any.
+func declaredIdentifier(stmt ast.Statement) *ast.Identifier {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		return s.Identifier
+	case *ast.ConstStatement:
+		return s.Identifier
+	case *ast.ImportStatement:
+		return s.Name
+	case *ast.ExportStatement:
+		return declaredIdentifier(s.Statement)
+	}
+	return nil
+}
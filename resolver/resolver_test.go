@@ -0,0 +1,142 @@
+package resolver
+
+import (
+	"../ast"
+	"../lexer"
+	"../parser"
+	"testing"
+)
+
+func TestResolveDiagnostics(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected []string
+	}{
+		{
+			input:    "let x = 5; -x;",
+			expected: nil,
+		},
+		{
+			input:    "let x = y;",
+			expected: []string{"1:5: warning: x declared and not used", "1:9: undefined: y"},
+		},
+		{
+			input:    "x;\nlet x = 1;",
+			expected: []string{"1:1: x used before its definition at 2:5", "2:5: warning: x declared and not used"},
+		},
+		{
+			input:    "x = 5;\nlet y = y = 1;",
+			expected: []string{"1:1: undefined: x", "2:5: warning: y declared and not used", "2:9: y used before its definition at 2:5"},
+		},
+		{
+			input:    "while (1) { let x = 1; }\nx = 2;\ntry { } catch (e) { }\ne = 3;",
+			expected: []string{"1:17: warning: x declared and not used", "2:1: undefined: x", "4:1: undefined: e"},
+		},
+		{
+			input:    "const x = 1;\nx = 2;\nwhile (x) { x += 1; }",
+			expected: []string{"2:1: cannot assign to constant x (declared at 1:7)", "3:13: cannot assign to constant x (declared at 1:7)"},
+		},
+		{
+			input:    "let x = 1;\nconst x = 2;\nimport \"a/util\";\nimport \"b/util\";\n!x;",
+			expected: []string{"2:7: x redeclared in this block (previous let declaration at 1:5)", "4:8: util redeclared in this block (previous import declaration at 3:8)"},
+		},
+		{
+			input:    "let xs = 1;\nfor (x in xs) { let x = 2; !x; }\ntry { } catch (e) { const e = 3; !e; }",
+			expected: []string{"2:6: warning: x declared and not used"},
+		},
+		{
+			input:    "let x = 1;\nx = 2;",
+			expected: []string{"1:5: warning: x declared and not used"},
+		},
+		{
+			input:    "let x = 1;\nx += 2;\nreturn x;",
+			expected: nil,
+		},
+		{
+			input:    "let n = 1;\nwhile (n) {\n  let tmp = n;\n  n = 0;\n}",
+			expected: []string{"3:7: warning: tmp declared and not used"},
+		},
+		{
+			input:    "let xs = 1;\nfor (x in xs) { break; }",
+			expected: []string{"2:6: warning: x declared and not used"},
+		},
+		{
+			input:    "let xs = 1;\nfor (x in xs) { !x; }\n!x;",
+			expected: []string{"3:2: undefined: x"},
+		},
+		{
+			input:    "const limit = 1;\nwhile (limit) { !later; }\nlet later = 2;",
+			expected: []string{"2:18: later used before its definition at 3:5", "3:5: warning: later declared and not used"},
+		},
+
+		{
+			input:    "import \"lib/strings\";\nimport \"./util.mk\";",
+			expected: nil,
+		},
+		{
+			input:    "export let a = 1;\nexport const b = 2;\nlet c = 3;",
+			expected: []string{"3:5: warning: c declared and not used"},
+		},
+		{
+			input:    "try { throw 1; } catch (e) { } finally { let f = 2; }",
+			expected: []string{"1:46: warning: f declared and not used"},
+		},
+		{
+			input:    "try { throw x; } catch (e) { throw e; }\n!e;",
+			expected: []string{"1:13: undefined: x", "2:2: undefined: e"},
+		},
+	}
+
+	for _, tc := range tt {
+		result := Resolve(parse(t, tc.input))
+
+		var got []string
+		for _, d := range result.Diagnostics {
+			got = append(got, d.String())
+		}
+
+		if len(got) != len(tc.expected) {
+			t.Errorf("wrong diagnostics for %q.\nExpected = %q\ngot      = %q\n", tc.input, tc.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.expected[i] {
+				t.Errorf("wrong diagnostics for %q.\nExpected = %q\ngot      = %q\n", tc.input, tc.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestResolveBindsInnermostDeclaration(t *testing.T) {
+	program := parse(t, "let x = 1;\nwhile (x) {\n  let x = 2;\n  -x;\n}")
+	result := Resolve(program)
+
+	if result.HasErrors() {
+		t.Fatalf("unexpected errors: %v\n", result.Diagnostics)
+	}
+
+	outer := program.Statements[0].(*ast.LetStatement).Identifier
+	while := program.Statements[1].(*ast.WhileStatement)
+	inner := while.Body.Statements[0].(*ast.LetStatement).Identifier
+	use := while.Body.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.PrefixExpression).Right.(*ast.Identifier)
+
+	if b := result.Uses[while.Condition.(*ast.Identifier)]; b == nil || b.Name != outer {
+		t.Errorf("while condition should refer to the outer x. Got = %v\n", b)
+	}
+
+	if b := result.Uses[use]; b == nil || b.Name != inner {
+		t.Errorf("-x should refer to the inner x. Got = %v\n", b)
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if errs := p.Errors(); len(errs) != 0 {
+		t.Fatalf("parser errors for %q: %v\n", input, errs)
+	}
+
+	return program
+}
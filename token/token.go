@@ -4,9 +4,25 @@ import "fmt"
 
 type TokenType string
 
+// Position is the location of a token in the source it was read from.
+// Lines and columns are counted from 1; the zero Position is invalid.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position
 }
 
 func (t Token) String() string {
@@ -18,8 +34,9 @@ const (
 	EOF     = "EOF"
 
 	// Identifiers and literals
-	IDENT = "IDENT"
-	INT   = "INT"
+	IDENT  = "IDENT"
+	INT    = "INT"
+	STRING = "STRING"
 
 	// Operators
 	ASSIGN    = "ASSIGN"
@@ -33,8 +50,15 @@ const (
 	EQ        = "EQ"
 	NEQ       = "NEQ"
 
+	// Compound assignment operators
+	PLUS_ASSIGN      = "PLUS_ASSIGN"
+	MINUS_ASSIGN     = "MINUS_ASSIGN"
+	ASTERISK_ASSIGN  = "ASTERISK_ASSIGN"
+	BACKSLASH_ASSIGN = "BACKSLASH_ASSIGN"
+
 	// Special characters
 	COMMA     = "COMMA"
+	COLON     = "COLON"
 	SEMICOLON = "SEMICOLON"
 	LPAREN    = "LPAREN"
 	RPAREN    = "RPAREN"
@@ -49,20 +73,44 @@ const (
 	ELSE     = "ELSE"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	CONST    = "CONST"
+	IMPORT   = "IMPORT"
+	EXPORT   = "EXPORT"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
 )
 
 var keywordToTokenType = map[string]TokenType{
-	"fn":     FUNCTION,
-	"return": RETURN,
-	"let":    LET,
-	"if":     IF,
-	"else":   ELSE,
-	"true":   TRUE,
-	"false":  FALSE,
+	"fn":       FUNCTION,
+	"return":   RETURN,
+	"let":      LET,
+	"if":       IF,
+	"else":     ELSE,
+	"true":     TRUE,
+	"false":    FALSE,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"const":    CONST,
+	"import":   IMPORT,
+	"export":   EXPORT,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
 }
 
 func NewToken(tt TokenType, l string) Token {
-	return Token{tt, l}
+	return Token{Type: tt, Literal: l}
 }
 
 func GetTokenType(kw string) TokenType {
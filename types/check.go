@@ -0,0 +1,283 @@
+package types
+
+import (
+	"../ast"
+	"../resolver"
+	"../token"
+	"fmt"
+)
+
+// Error is a type error found at a position in the source.
+type Error struct {
+	Pos token.Position
+	Msg string
+}
+
+func (e Error) String() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Info holds the types inferred for a program. Types that could not be
+// inferred are unbound type variables.
+type Info struct {
+	Types    map[ast.Expression]Type
+	Bindings map[*resolver.Binding]Type
+}
+
+type checker struct {
+	resolved *resolver.Result
+	// declarations maps the declaring identifier of each binding back to it.
+	declarations map[*ast.Identifier]*resolver.Binding
+	info         *Info
+	errors       []Error
+	nextVar      int
+}
+
+// Check infers the type of every expression and binding in program, using
+// the bindings found by resolver.Resolve, and reports expressions whose
+// types conflict with each other or with an annotation.
+func Check(program *ast.Program, resolved *resolver.Result) (*Info, []Error) {
+	c := &checker{
+		resolved:     resolved,
+		declarations: make(map[*ast.Identifier]*resolver.Binding),
+		info: &Info{
+			Types:    make(map[ast.Expression]Type),
+			Bindings: make(map[*resolver.Binding]Type),
+		},
+	}
+	for _, b := range resolved.Bindings {
+		c.declarations[b.Name] = b
+	}
+
+	c.statements(program.Statements)
+
+	for exp, t := range c.info.Types {
+		c.info.Types[exp] = prune(t)
+	}
+	for b, t := range c.info.Bindings {
+		c.info.Bindings[b] = prune(t)
+	}
+
+	return c.info, c.errors
+}
+
+func (c *checker) statements(stmts []ast.Statement) {
+	for _, stmt := range stmts {
+		c.statement(stmt)
+	}
+}
+
+func (c *checker) statement(stmt ast.Statement) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		c.declaration(s.Identifier, s.Annotation, s.Value)
+	case *ast.ConstStatement:
+		c.declaration(s.Identifier, s.Annotation, s.Value)
+	case *ast.ExportStatement:
+		c.statement(s.Statement)
+	case *ast.ReturnStatement:
+		if s.ReturnValue != nil {
+			c.expression(s.ReturnValue)
+		}
+	case *ast.ExpressionStatement:
+		c.expression(s.Expression)
+	case *ast.BlockStatement:
+		c.statements(s.Statements)
+	case *ast.WhileStatement:
+		c.expression(s.Condition)
+		if s.Body != nil {
+			c.statements(s.Body.Statements)
+		}
+	case *ast.ForInStatement:
+		// Nothing is known about the elements of the iterable, so the loop
+		// variable keeps the fresh type variable it is given on first use.
+		c.expression(s.Iterable)
+		if s.Body != nil {
+			c.statements(s.Body.Statements)
+		}
+	case *ast.ThrowStatement:
+		// Any value can be thrown, so the catch parameter is likewise left
+		// as a type variable.
+		c.expression(s.Value)
+	case *ast.TryStatement:
+		for _, block := range []*ast.BlockStatement{s.Body, s.Catch, s.Finally} {
+			if block != nil {
+				c.statements(block.Statements)
+			}
+		}
+	}
+}
+
+func (c *checker) declaration(ident *ast.Identifier, annotation *ast.TypeAnnotation, value ast.Expression) {
+	valueType := c.expression(value)
+
+	if annotation != nil {
+		annotated, ok := basicTypes[annotation.Name]
+		if !ok {
+			c.errorf(annotation.Token.Pos, "unknown type %s", annotation.Name)
+		} else {
+			if !unify(annotated, valueType) {
+				c.errorf(position(value), "cannot use %s (type %s) as type %s in declaration of %s",
+					value, prune(valueType), annotated, ident.Value)
+			}
+			// The binding has the annotated type even if the value doesn't
+			// match it, so that later uses aren't reported again.
+			valueType = annotated
+		}
+	}
+
+	if b, ok := c.declarations[ident]; ok {
+		unify(c.bindingType(b), valueType)
+	}
+}
+
+func (c *checker) expression(exp ast.Expression) Type {
+	if exp == nil {
+		return c.newVar()
+	}
+
+	var t Type
+
+	switch e := exp.(type) {
+	case *ast.IntegerLiteral:
+		t = Int
+	case *ast.BooleanLiteral:
+		t = Bool
+	case *ast.StringLiteral:
+		t = String
+	case *ast.Identifier:
+		if b, ok := c.resolved.Uses[e]; ok {
+			t = c.bindingType(b)
+		} else {
+			t = c.newVar()
+		}
+	case *ast.PrefixExpression:
+		right := c.expression(e.Right)
+		switch e.Operator {
+		case "-":
+			if !unify(right, Int) {
+				c.errorf(e.Token.Pos, "operator - not defined on %s (type %s)", e.Right, prune(right))
+			}
+			t = Int
+		case "!":
+			t = Bool
+		default:
+			t = c.newVar()
+		}
+	case *ast.InfixExpression:
+		t = c.infix(e)
+	case *ast.AssignExpression:
+		t = c.assignment(e)
+	default:
+		t = c.newVar()
+	}
+
+	c.info.Types[exp] = t
+	return t
+}
+
+// infix checks a binary operation: + adds integers or concatenates strings,
+// the other arithmetic operators and the orderings take integers, and == and
+// != compare operands of the same type.
+func (c *checker) infix(ie *ast.InfixExpression) Type {
+	left := c.expression(ie.Left)
+	right := c.expression(ie.Right)
+
+	switch ie.Operator {
+	case "+":
+		if !unify(left, right) {
+			c.mismatch(ie, left, right)
+		} else if prune(left) == Bool {
+			c.errorf(position(ie), "operator + not defined on %s (type %s)", ie.Left, prune(left))
+		}
+		return left
+	case "-", "*", "/", "<", ">":
+		if !unify(left, Int) {
+			c.errorf(position(ie.Left), "operator %s not defined on %s (type %s)", ie.Operator, ie.Left, prune(left))
+		}
+		if !unify(right, Int) {
+			c.errorf(position(ie.Right), "operator %s not defined on %s (type %s)", ie.Operator, ie.Right, prune(right))
+		}
+		if ie.Operator == "<" || ie.Operator == ">" {
+			return Bool
+		}
+		return Int
+	case "==", "!=":
+		if !unify(left, right) {
+			c.mismatch(ie, left, right)
+		}
+		return Bool
+	}
+	return c.newVar()
+}
+
+func (c *checker) mismatch(ie *ast.InfixExpression, left, right Type) {
+	c.errorf(position(ie), "invalid operation: %s (mismatched types %s and %s)", ie, prune(left), prune(right))
+}
+
+func (c *checker) assignment(ae *ast.AssignExpression) Type {
+	var target Type
+	if b, ok := c.resolved.Uses[ae.Target]; ok {
+		target = c.bindingType(b)
+	} else {
+		target = c.newVar()
+	}
+	c.info.Types[ae.Target] = target
+
+	value := c.expression(ae.Value)
+
+	if ae.Operator == "=" {
+		if !unify(target, value) {
+			c.errorf(position(ae.Value), "cannot assign %s (type %s) to %s (type %s)",
+				ae.Value, prune(value), ae.Target, prune(target))
+		}
+		return target
+	}
+
+	if !unify(target, Int) {
+		c.errorf(ae.Token.Pos, "operator %s not defined on %s (type %s)", ae.Operator, ae.Target, prune(target))
+	}
+	if !unify(value, Int) {
+		c.errorf(position(ae.Value), "operator %s not defined on %s (type %s)", ae.Operator, ae.Value, prune(value))
+	}
+	return Int
+}
+
+func (c *checker) bindingType(b *resolver.Binding) Type {
+	if t, ok := c.info.Bindings[b]; ok {
+		return t
+	}
+	t := c.newVar()
+	c.info.Bindings[b] = t
+	return t
+}
+
+func (c *checker) newVar() *Var {
+	c.nextVar++
+	return &Var{id: c.nextVar}
+}
+
+func (c *checker) errorf(pos token.Position, format string, args ...interface{}) {
+	c.errors = append(c.errors, Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// position returns the position at which exp starts in the source.
+func position(exp ast.Expression) token.Position {
+	switch e := exp.(type) {
+	case *ast.Identifier:
+		return e.Token.Pos
+	case *ast.IntegerLiteral:
+		return e.Token.Pos
+	case *ast.BooleanLiteral:
+		return e.Token.Pos
+	case *ast.StringLiteral:
+		return e.Token.Pos
+	case *ast.PrefixExpression:
+		return e.Token.Pos
+	case *ast.InfixExpression:
+		return position(e.Left)
+	case *ast.AssignExpression:
+		return position(e.Target)
+	}
+	return token.Position{}
+}
@@ -0,0 +1,121 @@
+package types
+
+import (
+	"../lexer"
+	"../parser"
+	"../resolver"
+	"testing"
+)
+
+func TestCheckErrors(t *testing.T) {
+	tt := []struct {
+		input    string
+		expected []string
+	}{
+		{input: "let x: int = 5; -x;", expected: nil},
+		{input: "let x = 5; let y: int = -x;", expected: nil},
+		{input: "let b: bool = !5; b = !b;", expected: nil},
+		{input: "let b: bool = true; b = false == !b;", expected: nil},
+		{
+			input:    "let n = 1;\nn = true;",
+			expected: []string{"2:5: cannot assign true (type bool) to n (type int)"},
+		},
+		{input: "let n: int = 1 + 2 * 3 - 4 / 5; let s: string = \"a\" + \"b\"; let b: bool = n < 1 == !s;", expected: nil},
+		{
+			input:    "let s = \"a\";\ns + 1;",
+			expected: []string{"2:1: invalid operation: (s + 1) (mismatched types string and int)"},
+		},
+		{
+			input:    "let b = !1;\nb + b;\n1 - b;\nb == 1;",
+			expected: []string{"2:1: operator + not defined on b (type bool)", "3:5: operator - not defined on b (type bool)", "4:1: invalid operation: (b == 1) (mismatched types bool and int)"},
+		},
+		{input: "try { throw \"boom\"; } catch (e) { -e; throw !e; }", expected: nil},
+		{
+			input:    "let n = 1;\ntry { n = !n; } finally { }",
+			expected: []string{"2:11: cannot assign !n (type bool) to n (type int)"},
+		},
+		{
+			input:    "let x: int = !5;",
+			expected: []string{"1:14: cannot use !5 (type bool) as type int in declaration of x"},
+		},
+		{
+			input:    "let b = !5;\nlet x: int = b;",
+			expected: []string{"2:14: cannot use b (type bool) as type int in declaration of x"},
+		},
+		{
+			input:    "let b = !5;\n-b;",
+			expected: []string{"2:1: operator - not defined on b (type bool)"},
+		},
+		{
+			input:    "let x = 5;\nx = !x;",
+			expected: []string{"2:5: cannot assign !x (type bool) to x (type int)"},
+		},
+		{
+			input:    "let b = !5;\nb += 1;",
+			expected: []string{"2:3: operator += not defined on b (type bool)"},
+		},
+		{
+			input:    "let s = \"monkey\";\n-s;",
+			expected: []string{"2:1: operator - not defined on s (type string)"},
+		},
+		{
+			input:    "export let s: string = 5;",
+			expected: []string{"1:24: cannot use 5 (type int) as type string in declaration of s"},
+		},
+		{
+			input:    "let x: float = 5;",
+			expected: []string{"1:8: unknown type float"},
+		},
+		{
+			input:    "let xs = 1;\nfor (x in xs) { let y: bool = x; -x; }",
+			expected: []string{"2:34: operator - not defined on x (type bool)"},
+		},
+	}
+
+	for _, tc := range tt {
+		_, errs := check(t, tc.input)
+
+		if len(errs) != len(tc.expected) {
+			t.Errorf("wrong errors for %q.\nExpected = %q\ngot      = %v\n", tc.input, tc.expected, errs)
+			continue
+		}
+		for i, err := range errs {
+			if err.String() != tc.expected[i] {
+				t.Errorf("wrong errors for %q.\nExpected = %q\ngot      = %v\n", tc.input, tc.expected, errs)
+				break
+			}
+		}
+	}
+}
+
+func TestCheckInfersUnannotatedBindings(t *testing.T) {
+	input := "let xs = 1;\nfor (x in xs) { let y = x; let z: bool = y; }"
+
+	info, errs := check(t, input)
+	if len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v\n", errs)
+	}
+
+	found := map[string]Type{}
+	for b, typ := range info.Bindings {
+		found[b.Name.Value] = typ
+	}
+
+	expected := map[string]Type{"xs": Int, "x": Bool, "y": Bool, "z": Bool}
+	for name, typ := range expected {
+		if found[name] != typ {
+			t.Errorf("binding %s has the wrong type. Expected = %s, got = %v\n", name, typ, found[name])
+		}
+	}
+}
+
+func check(t *testing.T, input string) (*Info, []Error) {
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+
+	if errs := p.Errors(); len(errs) != 0 {
+		t.Fatalf("parser errors for %q: %v\n", input, errs)
+	}
+
+	return Check(program, resolver.Resolve(program))
+}
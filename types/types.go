@@ -0,0 +1,73 @@
+// Package types infers the types of Monkey expressions and checks them
+// against optional annotations such as let x: int = 5;. Unannotated code is
+// inferred by unification, so annotations can be adopted gradually.
+package types
+
+import "fmt"
+
+type Type interface {
+	String() string
+}
+
+// Basic is a predeclared type that can be named in an annotation.
+type Basic struct {
+	name string
+}
+
+func (b *Basic) String() string {
+	return b.name
+}
+
+var (
+	Int    = &Basic{"int"}
+	Bool   = &Basic{"bool"}
+	String = &Basic{"string"}
+)
+
+var basicTypes = map[string]*Basic{
+	"int":    Int,
+	"bool":   Bool,
+	"string": String,
+}
+
+// Var is a type variable: a type that is not known yet. Unifying a Var with
+// another type binds it to that type.
+type Var struct {
+	id       int
+	instance Type
+}
+
+func (v *Var) String() string {
+	if v.instance != nil {
+		return v.instance.String()
+	}
+	return fmt.Sprintf("t%d", v.id)
+}
+
+// prune follows the instances of bound type variables and returns the type
+// they stand for, which is either a Basic or an unbound Var.
+func prune(t Type) Type {
+	if v, ok := t.(*Var); ok && v.instance != nil {
+		v.instance = prune(v.instance)
+		return v.instance
+	}
+	return t
+}
+
+// unify makes a and b the same type by binding type variables, and reports
+// whether that was possible.
+func unify(a, b Type) bool {
+	a, b = prune(a), prune(b)
+
+	if va, ok := a.(*Var); ok {
+		if a != b {
+			va.instance = b
+		}
+		return true
+	}
+	if _, ok := b.(*Var); ok {
+		return unify(b, a)
+	}
+
+	return a == b
+}